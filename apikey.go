@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"portfolio-api/auth"
+	"portfolio-api/config"
+	"portfolio-api/database"
+	"portfolio-api/models"
+	"portfolio-api/repository"
+)
+
+const apiKeyUsage = "usage: apikey create <name> [user_id] | list | revoke <id>"
+
+// runAPIKey implements the `apikey create|list|revoke` subcommand
+func runAPIKey(cfg config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(apiKeyUsage)
+	}
+
+	if cfg.StorageDriver == database.DriverMemory {
+		return errors.New("the memory storage driver cannot persist API keys")
+	}
+
+	repos, err := openRepositories(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "create":
+		if len(args) < 2 || len(args) > 3 {
+			return errors.New(apiKeyUsage)
+		}
+
+		key, prefix, hash, err := auth.GenerateAPIKey()
+		if err != nil {
+			return err
+		}
+
+		apiKey := &models.APIKey{Name: args[1], Prefix: prefix, KeyHash: hash}
+		if len(args) == 3 {
+			apiKey.UserID = args[2]
+		}
+		if err := repos.APIKeys.Create(ctx, apiKey); err != nil {
+			return err
+		}
+
+		fmt.Printf("Created API key %s (%s)\n", apiKey.ID, apiKey.Name)
+		fmt.Printf("Key: %s\n", key)
+		fmt.Println("Store it now; it cannot be shown again.")
+
+	case "list":
+		keys, err := repos.APIKeys.List(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tLAST USED\tSTATUS")
+		for _, key := range keys {
+			lastUsed := "never"
+			if key.LastUsedAt != nil {
+				lastUsed = key.LastUsedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			status := "active"
+			if key.RevokedAt != nil {
+				status = "revoked"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.Prefix, lastUsed, status)
+		}
+		return w.Flush()
+
+	case "revoke":
+		if len(args) != 2 {
+			return errors.New(apiKeyUsage)
+		}
+		if err := repos.APIKeys.Revoke(ctx, args[1]); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return fmt.Errorf("no active API key with id %s", args[1])
+			}
+			return err
+		}
+		fmt.Printf("Revoked API key %s\n", args[1])
+
+	default:
+		return fmt.Errorf("unknown apikey command %q: %s", args[0], apiKeyUsage)
+	}
+
+	return nil
+}
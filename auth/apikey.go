@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// APIKeyPrefix marks a credential as an API key rather than a JWT
+const APIKeyPrefix = "pk_"
+
+// displayPrefixLen is how much of a key is kept in clear text for identification
+const displayPrefixLen = 11
+
+// GenerateAPIKey returns a new random key, its display prefix and its hash.
+// The key itself is shown once and never stored.
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	key = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:displayPrefixLen], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex SHA-256 digest stored in api_keys.key_hash.
+// Keys carry 256 bits of entropy, so a fast unsalted hash is sufficient.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey reports whether a credential looks like an API key
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"portfolio-api/models"
+	"portfolio-api/repository"
+)
+
+// Authenticator resolves API keys and bearer JWTs into a Principal
+type Authenticator struct {
+	keys  repository.APIKeyRepository
+	users repository.UserRepository
+	jwt   *JWTVerifier
+}
+
+// NewAuthenticator creates an Authenticator; verifier may be nil to accept API keys only
+func NewAuthenticator(keys repository.APIKeyRepository, users repository.UserRepository, verifier *JWTVerifier) *Authenticator {
+	return &Authenticator{keys: keys, users: users, jwt: verifier}
+}
+
+// Required rejects requests without valid credentials with 401 and attaches
+// the Principal to the gin.Context otherwise
+func (a *Authenticator) Required() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := a.authenticate(c)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="portfolio-api"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		SetPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+// Optional attaches the Principal of requests with credentials and lets
+// anonymous requests through, so public endpoints can show callers more.
+// Invalid credentials are still rejected with 401.
+func (a *Authenticator) Optional() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if credential(c) == "" {
+			c.Next()
+			return
+		}
+
+		principal, err := a.authenticate(c)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="portfolio-api"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		SetPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+// RequireRole rejects authenticated callers whose role is not listed with 403.
+// It must run after Required.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := PrincipalFrom(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+
+		for _, role := range roles {
+			if principal.Role == role {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+	}
+}
+
+// credential extracts the key or token from X-API-Key or Authorization: Bearer
+func credential(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+
+	header := c.GetHeader("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func (a *Authenticator) authenticate(c *gin.Context) (*Principal, error) {
+	cred := credential(c)
+	if cred == "" {
+		return nil, errors.New("Authentication required")
+	}
+
+	if IsAPIKey(cred) {
+		return a.authenticateAPIKey(c, cred)
+	}
+	return a.authenticateJWT(c, cred)
+}
+
+func (a *Authenticator) authenticateAPIKey(c *gin.Context, key string) (*Principal, error) {
+	ctx := c.Request.Context()
+
+	apiKey, err := a.keys.GetByHash(ctx, HashAPIKey(key))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, errors.New("Invalid API key")
+	}
+	if err != nil {
+		log.Printf("Warning: API key lookup failed: %v", err)
+		return nil, errors.New("Unable to verify API key")
+	}
+	if apiKey.RevokedAt != nil {
+		return nil, errors.New("API key has been revoked")
+	}
+
+	// Keys not tied to a user are service keys created by an operator
+	role := models.RoleAdmin
+	if apiKey.UserID != "" {
+		user, err := a.users.Get(ctx, apiKey.UserID)
+		if err != nil {
+			log.Printf("Warning: failed to load user for API key %s: %v", apiKey.ID, err)
+			return nil, errors.New("Unable to verify API key")
+		}
+		role = user.Role
+	}
+
+	if err := a.keys.MarkUsed(ctx, apiKey.ID, time.Now()); err != nil {
+		log.Printf("Warning: failed to record API key usage: %v", err)
+	}
+
+	return &Principal{
+		Subject: apiKey.ID,
+		UserID:  apiKey.UserID,
+		Role:    role,
+		Method:  MethodAPIKey,
+	}, nil
+}
+
+func (a *Authenticator) authenticateJWT(c *gin.Context, token string) (*Principal, error) {
+	if a.jwt == nil {
+		return nil, errors.New("Bearer tokens are not accepted")
+	}
+
+	claims, err := a.jwt.Verify(token)
+	if err != nil {
+		return nil, errors.New("Invalid token")
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, errors.New("Token has no subject")
+	}
+
+	// Token subjects without a users row may sign in but not change anything
+	role := models.RoleViewer
+	user, err := a.users.Get(c.Request.Context(), subject)
+	switch {
+	case err == nil:
+		role = user.Role
+	case !errors.Is(err, repository.ErrNotFound):
+		log.Printf("Warning: failed to load user for token subject %s: %v", subject, err)
+		return nil, errors.New("Unable to verify token")
+	}
+
+	return &Principal{
+		Subject: subject,
+		UserID:  subject,
+		Role:    role,
+		Method:  MethodJWT,
+	}, nil
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"github.com/gin-gonic/gin"
+	"portfolio-api/models"
+)
+
+// Authentication methods recorded on a Principal
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+)
+
+// principalKey is the gin.Context key holding the authenticated Principal
+const principalKey = "auth.principal"
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// Subject is the API key ID or the JWT "sub" claim
+	Subject string `json:"subject"`
+	// UserID links the caller to a users row when known
+	UserID string `json:"user_id,omitempty"`
+	// Role is one of models.RoleAdmin, RoleOwner or RoleViewer
+	Role   string `json:"role"`
+	Method string `json:"method"`
+}
+
+// IsAdmin reports whether the caller may manage every resource
+func (p *Principal) IsAdmin() bool {
+	return p.Role == models.RoleAdmin
+}
+
+// CanManage reports whether the caller may change a resource owned by userID.
+// Admins manage everything; owners only what carries their own user ID.
+func (p *Principal) CanManage(userID string) bool {
+	if p.IsAdmin() {
+		return true
+	}
+	return p.Role == models.RoleOwner && p.UserID != "" && p.UserID == userID
+}
+
+// SetPrincipal attaches p to the request context
+func SetPrincipal(c *gin.Context, p *Principal) {
+	c.Set(principalKey, p)
+}
+
+// PrincipalFrom returns the authenticated caller, if any
+func PrincipalFrom(c *gin.Context) (*Principal, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return nil, false
+	}
+	p, ok := value.(*Principal)
+	return p, ok
+}
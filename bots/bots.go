@@ -0,0 +1,108 @@
+// Package bots flags visits made by crawlers, monitors, automated browsers
+// and excluded networks so they can be left out of the statistics
+package bots
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"portfolio-api/spam"
+	"portfolio-api/useragent"
+)
+
+// Reasons a visit is flagged as a bot
+const (
+	ReasonUserAgent  = "user_agent"
+	ReasonHeadless   = "headless"
+	ReasonRate       = "rate"
+	ReasonExcludedIP = "excluded_ip"
+)
+
+// headlessPattern matches automated browsers that otherwise look like real ones
+var headlessPattern = regexp.MustCompile(`(?i)headless|phantomjs|puppeteer|playwright|selenium|webdriver|cypress|jsdom|slimerjs|splash`)
+
+// monitorPattern matches health checkers and uptime monitors not covered by
+// the generic bot signatures
+var monitorPattern = regexp.MustCompile(`(?i)kube-probe|elb-healthchecker|googlehc|statuscake|site24x7|freshping|betteruptime|newrelicpinger|datadog`)
+
+// Config tunes the detector
+type Config struct {
+	// ExcludedNetworks are IPs or CIDRs whose visits are always flagged,
+	// e.g. our own office
+	ExcludedNetworks []string
+	// MaxPerMinute flags a visitor's visits beyond this rate; 0 disables it
+	MaxPerMinute int
+}
+
+// Detector classifies visits as human or bot
+type Detector struct {
+	excluded []*net.IPNet
+	rate     *spam.RateLimiter
+}
+
+// Client describes who made a visit
+type Client struct {
+	IP        string
+	UserAgent string
+	// Info is the parsed UserAgent
+	Info useragent.Info
+	// VisitorID keys the rate heuristic so no IP addresses are kept
+	VisitorID string
+}
+
+// NewDetector creates a Detector, rejecting malformed excluded networks
+func NewDetector(cfg Config) (*Detector, error) {
+	d := &Detector{rate: spam.NewRateLimiter(cfg.MaxPerMinute, time.Minute)}
+	for _, network := range cfg.ExcludedNetworks {
+		ipNet, err := parseNetwork(network)
+		if err != nil {
+			return nil, err
+		}
+		d.excluded = append(d.excluded, ipNet)
+	}
+	return d, nil
+}
+
+// Detect returns why the client looks like a bot, or "" for a human
+func (d *Detector) Detect(client Client, now time.Time) string {
+	if ip := net.ParseIP(client.IP); ip != nil {
+		for _, network := range d.excluded {
+			if network.Contains(ip) {
+				return ReasonExcludedIP
+			}
+		}
+	}
+
+	// Browsers always send a User-Agent
+	if client.Info.DeviceType == useragent.Bot || strings.TrimSpace(client.UserAgent) == "" ||
+		monitorPattern.MatchString(client.UserAgent) {
+		return ReasonUserAgent
+	}
+	if headlessPattern.MatchString(client.UserAgent) {
+		return ReasonHeadless
+	}
+
+	if ok, _ := d.rate.Allow(client.VisitorID, now); !ok {
+		return ReasonRate
+	}
+	return ""
+}
+
+// parseNetwork accepts a CIDR or a single IP address
+func parseNetwork(network string) (*net.IPNet, error) {
+	if ip := net.ParseIP(network); ip != nil {
+		bits := 128
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 32
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, ipNet, err := net.ParseCIDR(network)
+	if err != nil {
+		return nil, fmt.Errorf("invalid excluded network %q", network)
+	}
+	return ipNet, nil
+}
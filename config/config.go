@@ -0,0 +1,188 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"portfolio-api/database"
+)
+
+// Config holds the runtime settings read from the environment
+type Config struct {
+	Port string
+
+	// StorageDriver selects the backend: postgres, supabase, sqlite or memory
+	StorageDriver string
+	DatabaseURL   string
+	SupabaseURL   string
+	SupabaseDBURL string
+	SQLitePath    string
+
+	// JWT verification for bearer tokens (HS256 secret and/or RS256 JWKS file)
+	JWTSecret   string
+	JWKSFile    string
+	JWTIssuer   string
+	JWTAudience string
+
+	// Outgoing email; notifications are disabled when SMTPHost is empty
+	SMTPHost         string
+	SMTPPort         string
+	SMTPUsername     string
+	SMTPPassword     string
+	SMTPFrom         string
+	EmailTemplateDir string
+
+	// Contact form emails
+	ContactNotifyEmail string
+	ContactAutoReply   bool
+	SiteName           string
+	SiteURL            string
+
+	// Contact form spam protection
+	SpamThreshold         int
+	SpamMinSubmitSeconds  int
+	SpamTokenSecret       string
+	SpamMaxLinks          int
+	SpamKeywords          []string
+	ContactRateLimitIP    int
+	ContactRateLimitEmail int
+	CaptchaProvider       string
+	CaptchaSecret         string
+
+	// GeoIPDBPath is a MaxMind-format .mmdb file used to locate visitors
+	GeoIPDBPath string
+
+	// Bot detection for visit tracking
+	BotExcludedNetworks []string
+	BotMaxVisitsPerMin  int
+
+	// ReferrerRulesFile extends the built-in referrer channel rules
+	ReferrerRulesFile string
+
+	// Analytics rollups: how often visits are aggregated and how many days
+	// raw visits are kept after aggregation (0 keeps them forever)
+	RollupIntervalMinutes int
+	RetentionDays         int
+
+	// PublishIntervalSeconds is how often scheduled projects are checked for publishing
+	PublishIntervalSeconds int
+
+	// TrustedProxies limits which proxies may set X-Forwarded-For; empty trusts all
+	TrustedProxies []string
+}
+
+// Load reads the configuration from environment variables
+func Load() Config {
+	cfg := Config{
+		Port:          getEnv("PORT", "8080"),
+		StorageDriver: os.Getenv("STORAGE_DRIVER"),
+		DatabaseURL:   os.Getenv("DATABASE_URL"),
+		SupabaseURL:   os.Getenv("SUPABASE_URL"),
+		SupabaseDBURL: os.Getenv("SUPABASE_DB_URL"),
+		SQLitePath:    getEnv("SQLITE_PATH", "portfolio.db"),
+		JWTSecret:     os.Getenv("JWT_SECRET"),
+		JWKSFile:      os.Getenv("JWT_JWKS_FILE"),
+		JWTIssuer:     os.Getenv("JWT_ISSUER"),
+		JWTAudience:   os.Getenv("JWT_AUDIENCE"),
+
+		SMTPHost:         os.Getenv("SMTP_HOST"),
+		SMTPPort:         getEnv("SMTP_PORT", "587"),
+		SMTPUsername:     os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:     os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:         os.Getenv("SMTP_FROM"),
+		EmailTemplateDir: os.Getenv("EMAIL_TEMPLATE_DIR"),
+
+		ContactNotifyEmail: os.Getenv("CONTACT_NOTIFY_EMAIL"),
+		ContactAutoReply:   getBool("CONTACT_AUTOREPLY", false),
+		SiteName:           getEnv("SITE_NAME", "Portfolio"),
+		SiteURL:            os.Getenv("SITE_URL"),
+
+		SpamThreshold:         getInt("SPAM_THRESHOLD", 50),
+		SpamMinSubmitSeconds:  getInt("SPAM_MIN_SUBMIT_SECONDS", 3),
+		SpamTokenSecret:       os.Getenv("SPAM_TOKEN_SECRET"),
+		SpamMaxLinks:          getInt("SPAM_MAX_LINKS", 2),
+		SpamKeywords:          getList("SPAM_KEYWORDS"),
+		ContactRateLimitIP:    getInt("CONTACT_RATE_LIMIT_IP", 5),
+		ContactRateLimitEmail: getInt("CONTACT_RATE_LIMIT_EMAIL", 3),
+		CaptchaProvider:       os.Getenv("CAPTCHA_PROVIDER"),
+		CaptchaSecret:         os.Getenv("CAPTCHA_SECRET"),
+
+		GeoIPDBPath: os.Getenv("GEOIP_DB_PATH"),
+
+		BotExcludedNetworks: getList("BOT_EXCLUDED_NETWORKS"),
+		BotMaxVisitsPerMin:  getInt("BOT_MAX_VISITS_PER_MINUTE", 30),
+
+		ReferrerRulesFile: os.Getenv("REFERRER_RULES_FILE"),
+
+		RollupIntervalMinutes: getInt("ANALYTICS_ROLLUP_INTERVAL_MINUTES", 15),
+		RetentionDays:         getInt("ANALYTICS_RETENTION_DAYS", 0),
+
+		PublishIntervalSeconds: getInt("PUBLISH_INTERVAL_SECONDS", 60),
+
+		TrustedProxies: getList("TRUSTED_PROXIES"),
+	}
+
+	// Without an explicit driver, keep existing deployments working and
+	// fall back to a local SQLite file when no database is configured
+	if cfg.StorageDriver == "" {
+		switch {
+		case cfg.SupabaseDBURL != "":
+			cfg.StorageDriver = database.DriverSupabase
+		case cfg.DatabaseURL != "":
+			cfg.StorageDriver = database.DriverPostgres
+		default:
+			cfg.StorageDriver = database.DriverSQLite
+		}
+	}
+
+	return cfg
+}
+
+// StorageDSN returns the connection string for the selected storage driver
+func (c Config) StorageDSN() string {
+	switch c.StorageDriver {
+	case database.DriverPostgres:
+		return c.DatabaseURL
+	case database.DriverSupabase:
+		return c.SupabaseDBURL
+	case database.DriverSQLite:
+		return c.SQLitePath
+	default:
+		return ""
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getList splits a comma separated variable, dropping empty entries
+func getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -1,146 +1,73 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
-	"os"
-
-	_ "github.com/lib/pq"
 )
 
-var DB *sql.DB
-
-// InitDB initializes the database connection
-func InitDB() error {
-	var err error
+// Dialect identifies the SQL flavour spoken by a connection
+type Dialect string
 
-	// Get database URL from environment variable
-	databaseURL := os.Getenv("DATABASE_URL")
-	if databaseURL == "" {
-		// Default to in-memory SQLite for development
-		log.Println("No DATABASE_URL found, using in-memory database")
-		return initSQLite()
-	}
-
-	// Connect to PostgreSQL
-	DB, err = sql.Open("postgres", databaseURL)
-	if err != nil {
-		return fmt.Errorf("failed to connect to database: %v", err)
-	}
+const (
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
 
-	// Test the connection
-	if err = DB.Ping(); err != nil {
-		return fmt.Errorf("failed to ping database: %v", err)
-	}
+// Storage drivers selectable with STORAGE_DRIVER
+const (
+	DriverPostgres = "postgres"
+	DriverSupabase = "supabase"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
 
-	log.Println("Successfully connected to PostgreSQL database")
+// DB is the connection opened by InitDB; it stays nil for the memory driver
+var DB *sql.DB
 
-	// Create tables if they don't exist
-	if err = createTables(); err != nil {
-		return fmt.Errorf("failed to create tables: %v", err)
+// Open connects to the backend selected by driver without touching the schema.
+// dsn is a connection URL for postgres/supabase and a file path for sqlite.
+func Open(driver, dsn string) (*sql.DB, Dialect, error) {
+	switch driver {
+	case DriverPostgres:
+		db, err := openPostgres("PostgreSQL", dsn, "DATABASE_URL")
+		return db, Postgres, err
+	case DriverSupabase:
+		db, err := openPostgres("Supabase", dsn, "SUPABASE_DB_URL")
+		return db, Postgres, err
+	case DriverSQLite:
+		db, err := openSQLite(dsn)
+		return db, SQLite, err
+	case DriverMemory:
+		return nil, "", fmt.Errorf("the memory driver has no database connection")
+	default:
+		return nil, "", fmt.Errorf("unknown storage driver %q", driver)
 	}
-
-	return nil
 }
 
-// initSQLite initializes an in-memory SQLite database for development
-func initSQLite() error {
-	var err error
-	DB, err = sql.Open("sqlite3", ":memory:")
+// InitDB opens the selected database and applies pending migrations
+func InitDB(driver, dsn string) (*sql.DB, Dialect, error) {
+	db, dialect, err := Open(driver, dsn)
 	if err != nil {
-		return fmt.Errorf("failed to open SQLite database: %v", err)
+		return nil, "", err
 	}
 
-	log.Println("Using in-memory SQLite database for development")
-	return createTables()
-}
-
-// createTables creates all necessary tables
-func createTables() error {
-	// Users table
-	usersTable := `
-	CREATE TABLE IF NOT EXISTS users (
-		id SERIAL PRIMARY KEY,
-		name VARCHAR(255) NOT NULL,
-		email VARCHAR(255) UNIQUE NOT NULL,
-		role VARCHAR(100) NOT NULL,
-		avatar TEXT,
-		bio TEXT,
-		website TEXT,
-		location VARCHAR(255),
-		skills JSONB,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);`
-
-	// Projects table
-	projectsTable := `
-	CREATE TABLE IF NOT EXISTS projects (
-		id SERIAL PRIMARY KEY,
-		title VARCHAR(255) NOT NULL,
-		description TEXT NOT NULL,
-		tech_stack JSONB,
-		status VARCHAR(50) NOT NULL,
-		featured BOOLEAN DEFAULT FALSE,
-		live_url TEXT,
-		github_url TEXT,
-		image_url TEXT,
-		start_date TIMESTAMP,
-		end_date TIMESTAMP,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);`
-
-	// Skills table
-	skillsTable := `
-	CREATE TABLE IF NOT EXISTS skills (
-		id SERIAL PRIMARY KEY,
-		name VARCHAR(255) NOT NULL,
-		category VARCHAR(100) NOT NULL,
-		level VARCHAR(50) NOT NULL,
-		years_exp INTEGER DEFAULT 0,
-		featured BOOLEAN DEFAULT FALSE,
-		icon TEXT,
-		color VARCHAR(20),
-		description TEXT
-	);`
-
-	// Contact messages table
-	contactTable := `
-	CREATE TABLE IF NOT EXISTS contact_messages (
-		id SERIAL PRIMARY KEY,
-		name VARCHAR(255) NOT NULL,
-		email VARCHAR(255) NOT NULL,
-		subject VARCHAR(255) NOT NULL,
-		message TEXT NOT NULL,
-		status VARCHAR(50) DEFAULT 'unread',
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		read_at TIMESTAMP
-	);`
-
-	// Visits table for analytics
-	visitsTable := `
-	CREATE TABLE IF NOT EXISTS visits (
-		id SERIAL PRIMARY KEY,
-		page VARCHAR(255) NOT NULL,
-		user_agent TEXT,
-		country VARCHAR(10),
-		referrer TEXT,
-		ip_address INET,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);`
-
-	tables := []string{usersTable, projectsTable, skillsTable, contactTable, visitsTable}
+	migrator, err := NewMigrator(db, dialect)
+	if err != nil {
+		db.Close()
+		return nil, "", err
+	}
 
-	for _, table := range tables {
-		if _, err := DB.Exec(table); err != nil {
-			return fmt.Errorf("failed to create table: %v", err)
-		}
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		db.Close()
+		return nil, "", fmt.Errorf("failed to migrate schema: %v", err)
 	}
+	log.Printf("Database schema is up to date (%d migrations applied)", applied)
 
-	log.Println("Database tables created successfully")
-	return nil
+	DB = db
+	return db, dialect, nil
 }
 
 // Close closes the database connection
@@ -149,4 +76,4 @@ func Close() error {
 		return DB.Close()
 	}
 	return nil
-}
\ No newline at end of file
+}
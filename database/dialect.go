@@ -0,0 +1,57 @@
+package database
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rebind converts a query written with Postgres $n placeholders into the
+// dialect's syntax. SQLite gets anonymous ? placeholders, and args are
+// expanded so that a $n used several times is bound at every occurrence.
+// SQLite stores times as text, so they are bound in UTC to stay readable
+// and in lexical order whatever offset the caller used.
+func (d Dialect) Rebind(query string, args []interface{}) (string, []interface{}) {
+	if d != SQLite {
+		return query, args
+	}
+
+	var b strings.Builder
+	bound := make([]interface{}, 0, len(args))
+
+	for i := 0; i < len(query); i++ {
+		if query[i] != '$' {
+			b.WriteByte(query[i])
+			continue
+		}
+
+		j := i + 1
+		for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+			j++
+		}
+		n, err := strconv.Atoi(query[i+1 : j])
+		if err != nil || n < 1 || n > len(args) {
+			b.WriteByte(query[i])
+			continue
+		}
+
+		b.WriteByte('?')
+		bound = append(bound, utcTime(args[n-1]))
+		i = j - 1
+	}
+
+	return b.String(), bound
+}
+
+// utcTime converts time arguments to UTC, passing other arguments through
+func utcTime(arg interface{}) interface{} {
+	switch v := arg.(type) {
+	case time.Time:
+		return v.UTC()
+	case sql.NullTime:
+		v.Time = v.Time.UTC()
+		return v
+	}
+	return arg
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS analytics;
+DROP TABLE IF EXISTS contact_messages;
+DROP TABLE IF EXISTS skills;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS users;
+DROP FUNCTION IF EXISTS update_updated_at_column();
@@ -0,0 +1,127 @@
+-- Core portfolio schema. Written to be safe on databases that were created by
+-- the old boot-time DDL, so existing policies and triggers are replaced.
+
+CREATE TABLE IF NOT EXISTS users (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	email VARCHAR(255) UNIQUE NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	role VARCHAR(100) NOT NULL DEFAULT 'user',
+	avatar_url TEXT,
+	bio TEXT,
+	website TEXT,
+	location VARCHAR(255),
+	skills JSONB DEFAULT '[]',
+	is_public BOOLEAN DEFAULT true,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS projects (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+	title VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL,
+	tech_stack JSONB DEFAULT '[]',
+	status VARCHAR(50) NOT NULL DEFAULT 'in-progress',
+	featured BOOLEAN DEFAULT false,
+	live_url TEXT,
+	github_url TEXT,
+	image_url TEXT,
+	start_date DATE,
+	end_date DATE,
+	is_public BOOLEAN DEFAULT true,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS skills (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+	name VARCHAR(255) NOT NULL,
+	category VARCHAR(100) NOT NULL,
+	level VARCHAR(50) NOT NULL,
+	years_exp INTEGER DEFAULT 0,
+	featured BOOLEAN DEFAULT false,
+	icon_url TEXT,
+	color VARCHAR(20),
+	description TEXT,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS contact_messages (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	name VARCHAR(255) NOT NULL,
+	email VARCHAR(255) NOT NULL,
+	subject VARCHAR(255) NOT NULL,
+	message TEXT NOT NULL,
+	status VARCHAR(50) DEFAULT 'unread',
+	ip_address INET,
+	user_agent TEXT,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	read_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS analytics (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	page VARCHAR(255) NOT NULL,
+	referrer TEXT,
+	user_agent TEXT,
+	ip_address INET,
+	country VARCHAR(10),
+	city VARCHAR(100),
+	device_type VARCHAR(50),
+	browser VARCHAR(100),
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- updated_at maintenance
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+	NEW.updated_at = NOW();
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+CREATE TRIGGER update_users_updated_at
+	BEFORE UPDATE ON users
+	FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_projects_updated_at ON projects;
+CREATE TRIGGER update_projects_updated_at
+	BEFORE UPDATE ON projects
+	FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Row Level Security only applies to Supabase, where the auth schema exists
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = 'auth') THEN
+		ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+		ALTER TABLE projects ENABLE ROW LEVEL SECURITY;
+
+		DROP POLICY IF EXISTS "Public profiles are viewable by everyone" ON users;
+		CREATE POLICY "Public profiles are viewable by everyone"
+			ON users FOR SELECT
+			USING (is_public = true);
+
+		DROP POLICY IF EXISTS "Users can update own profile" ON users;
+		CREATE POLICY "Users can update own profile"
+			ON users FOR UPDATE
+			USING (auth.uid() = id);
+
+		DROP POLICY IF EXISTS "Public projects are viewable by everyone" ON projects;
+		CREATE POLICY "Public projects are viewable by everyone"
+			ON projects FOR SELECT
+			USING (is_public = true);
+	END IF;
+END
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_projects_user_id ON projects(user_id);
+CREATE INDEX IF NOT EXISTS idx_projects_featured ON projects(featured);
+CREATE INDEX IF NOT EXISTS idx_projects_status ON projects(status);
+CREATE INDEX IF NOT EXISTS idx_skills_user_id ON skills(user_id);
+CREATE INDEX IF NOT EXISTS idx_skills_category ON skills(category);
+CREATE INDEX IF NOT EXISTS idx_analytics_page ON analytics(page);
+CREATE INDEX IF NOT EXISTS idx_analytics_created_at ON analytics(created_at);
@@ -0,0 +1,3 @@
+-- The original free-form role values are not restored
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'user';
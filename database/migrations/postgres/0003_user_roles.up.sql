@@ -0,0 +1,6 @@
+-- users.role now drives authorization: admin, owner or viewer. Existing
+-- free-form values (job titles) belong to portfolio owners.
+UPDATE users SET role = 'owner' WHERE role NOT IN ('admin', 'owner', 'viewer');
+
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'viewer';
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'owner', 'viewer'));
@@ -0,0 +1,2 @@
+ALTER TABLE contact_messages DROP COLUMN IF EXISTS spam_reasons;
+ALTER TABLE contact_messages DROP COLUMN IF EXISTS spam_score;
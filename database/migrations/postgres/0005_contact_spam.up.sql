@@ -0,0 +1,4 @@
+-- Spam scoring for contact form submissions; ip_address and user_agent
+-- already exist and are now populated
+ALTER TABLE contact_messages ADD COLUMN IF NOT EXISTS spam_score INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE contact_messages ADD COLUMN IF NOT EXISTS spam_reasons JSONB DEFAULT '[]';
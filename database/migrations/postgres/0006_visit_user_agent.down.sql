@@ -0,0 +1,2 @@
+ALTER TABLE analytics DROP COLUMN IF EXISTS os;
+ALTER TABLE analytics DROP COLUMN IF EXISTS browser_version;
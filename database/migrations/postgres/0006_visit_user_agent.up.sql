@@ -0,0 +1,4 @@
+-- User-agent classification of visits; device_type and browser already exist
+-- and are now populated
+ALTER TABLE analytics ADD COLUMN IF NOT EXISTS browser_version VARCHAR(50);
+ALTER TABLE analytics ADD COLUMN IF NOT EXISTS os VARCHAR(50);
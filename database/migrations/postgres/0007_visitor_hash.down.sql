@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS visitor_salts;
+DROP INDEX IF EXISTS idx_analytics_visitor_id;
+ALTER TABLE analytics DROP COLUMN IF EXISTS visitor_id;
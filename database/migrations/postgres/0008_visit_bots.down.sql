@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_analytics_is_bot_created_at;
+ALTER TABLE analytics DROP COLUMN IF EXISTS bot_reason;
+ALTER TABLE analytics DROP COLUMN IF EXISTS is_bot;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_analytics_visitor_id_created_at;
+ALTER TABLE analytics DROP COLUMN IF EXISTS ended_at;
@@ -0,0 +1,5 @@
+ALTER TABLE analytics DROP COLUMN IF EXISTS utm_campaign;
+ALTER TABLE analytics DROP COLUMN IF EXISTS utm_medium;
+ALTER TABLE analytics DROP COLUMN IF EXISTS utm_source;
+ALTER TABLE analytics DROP COLUMN IF EXISTS channel;
+ALTER TABLE analytics DROP COLUMN IF EXISTS referrer_host;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS analytics_daily;
+DROP TABLE IF EXISTS analytics_hourly;
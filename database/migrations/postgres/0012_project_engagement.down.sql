@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS project_clicks;
+DROP INDEX IF EXISTS idx_analytics_project_id;
+ALTER TABLE analytics DROP COLUMN IF EXISTS project_id;
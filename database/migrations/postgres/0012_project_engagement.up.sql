@@ -0,0 +1,17 @@
+-- Visits to a project's page carry its ID, and clicks on a project's live
+-- site and GitHub links are recorded by the /p/:id/:target redirects. Neither
+-- references projects, so engagement survives a project's deletion.
+ALTER TABLE analytics ADD COLUMN IF NOT EXISTS project_id UUID;
+CREATE INDEX IF NOT EXISTS idx_analytics_project_id ON analytics(project_id);
+
+CREATE TABLE IF NOT EXISTS project_clicks (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	project_id UUID NOT NULL,
+	target VARCHAR(20) NOT NULL,
+	visitor_id VARCHAR(64),
+	referrer TEXT,
+	is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+	bot_reason VARCHAR(20),
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_project_clicks_project_id ON project_clicks(project_id, target);
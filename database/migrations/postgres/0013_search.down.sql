@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_users_search;
+DROP INDEX IF EXISTS idx_skills_search;
+DROP INDEX IF EXISTS idx_projects_search;
+ALTER TABLE users DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE skills DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE projects DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,24 @@
+-- Full-text search over projects, skills and user profiles. The "simple"
+-- configuration neither stems nor drops stop words, so Korean and English
+-- text tokenize alike. Weights: A names and titles, B tech stacks, skill
+-- categories and profile skills, C descriptions, locations and bios.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple'::regconfig, COALESCE(title, '')), 'A') ||
+	setweight(jsonb_to_tsvector('simple'::regconfig, COALESCE(tech_stack, '[]'::jsonb), '["string"]'), 'B') ||
+	setweight(to_tsvector('simple'::regconfig, COALESCE(description, '')), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_projects_search ON projects USING GIN (search_vector);
+
+ALTER TABLE skills ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple'::regconfig, COALESCE(name, '')), 'A') ||
+	setweight(to_tsvector('simple'::regconfig, COALESCE(category, '')), 'B') ||
+	setweight(to_tsvector('simple'::regconfig, COALESCE(description, '')), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_skills_search ON skills USING GIN (search_vector);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple'::regconfig, COALESCE(name, '')), 'A') ||
+	setweight(jsonb_to_tsvector('simple'::regconfig, COALESCE(skills, '[]'::jsonb), '["string"]'), 'B') ||
+	setweight(to_tsvector('simple'::regconfig, COALESCE(location, '') || ' ' || COALESCE(bio, '')), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_users_search ON users USING GIN (search_vector);
@@ -0,0 +1,3 @@
+-- Serves the tech_stack @> '["Go"]' containment tests behind the tech filter
+-- of the project lists
+CREATE INDEX IF NOT EXISTS idx_projects_tech_stack ON projects USING GIN (tech_stack jsonb_path_ops);
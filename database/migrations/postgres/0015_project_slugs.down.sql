@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS project_slugs;
+DROP INDEX IF EXISTS idx_projects_slug;
+ALTER TABLE projects DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,15 @@
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = 'auth') THEN
+		DROP POLICY IF EXISTS "Public projects are viewable by everyone" ON projects;
+		CREATE POLICY "Public projects are viewable by everyone"
+			ON projects FOR SELECT
+			USING (is_public = true);
+	END IF;
+END
+$$;
+
+DROP INDEX IF EXISTS idx_projects_publication;
+ALTER TABLE projects DROP COLUMN IF EXISTS published_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS publication;
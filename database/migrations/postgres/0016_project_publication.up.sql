@@ -0,0 +1,20 @@
+-- Projects move through a publication workflow separate from their status:
+-- draft, scheduled for publish_at, published and archived. Existing projects
+-- were all public, so they start out published.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS publication VARCHAR(20) NOT NULL DEFAULT 'published';
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITH TIME ZONE;
+UPDATE projects SET published_at = created_at WHERE publication = 'published' AND published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_projects_publication ON projects(publication, publish_at);
+
+-- On Supabase only published projects are public
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = 'auth') THEN
+		DROP POLICY IF EXISTS "Public projects are viewable by everyone" ON projects;
+		CREATE POLICY "Public projects are viewable by everyone"
+			ON projects FOR SELECT
+			USING (publication = 'published');
+	END IF;
+END
+$$;
@@ -0,0 +1,83 @@
+-- Core portfolio schema for SQLite. Identifiers and timestamps are generated
+-- by the application, JSON arrays are stored as TEXT.
+
+CREATE TABLE IF NOT EXISTS users (
+	id TEXT PRIMARY KEY,
+	email VARCHAR(255) UNIQUE NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	role VARCHAR(100) NOT NULL DEFAULT 'user',
+	avatar_url TEXT,
+	bio TEXT,
+	website TEXT,
+	location VARCHAR(255),
+	skills TEXT DEFAULT '[]',
+	is_public BOOLEAN DEFAULT 1,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS projects (
+	id TEXT PRIMARY KEY,
+	user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+	title VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL,
+	tech_stack TEXT DEFAULT '[]',
+	status VARCHAR(50) NOT NULL DEFAULT 'in-progress',
+	featured BOOLEAN DEFAULT 0,
+	live_url TEXT,
+	github_url TEXT,
+	image_url TEXT,
+	start_date DATE,
+	end_date DATE,
+	is_public BOOLEAN DEFAULT 1,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS skills (
+	id TEXT PRIMARY KEY,
+	user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+	name VARCHAR(255) NOT NULL,
+	category VARCHAR(100) NOT NULL,
+	level VARCHAR(50) NOT NULL,
+	years_exp INTEGER DEFAULT 0,
+	featured BOOLEAN DEFAULT 0,
+	icon_url TEXT,
+	color VARCHAR(20),
+	description TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS contact_messages (
+	id TEXT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	email VARCHAR(255) NOT NULL,
+	subject VARCHAR(255) NOT NULL,
+	message TEXT NOT NULL,
+	status VARCHAR(50) DEFAULT 'unread',
+	ip_address TEXT,
+	user_agent TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	read_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS analytics (
+	id TEXT PRIMARY KEY,
+	page VARCHAR(255) NOT NULL,
+	referrer TEXT,
+	user_agent TEXT,
+	ip_address TEXT,
+	country VARCHAR(10),
+	city VARCHAR(100),
+	device_type VARCHAR(50),
+	browser VARCHAR(100),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_projects_user_id ON projects(user_id);
+CREATE INDEX IF NOT EXISTS idx_projects_featured ON projects(featured);
+CREATE INDEX IF NOT EXISTS idx_projects_status ON projects(status);
+CREATE INDEX IF NOT EXISTS idx_skills_user_id ON skills(user_id);
+CREATE INDEX IF NOT EXISTS idx_skills_category ON skills(category);
+CREATE INDEX IF NOT EXISTS idx_analytics_page ON analytics(page);
+CREATE INDEX IF NOT EXISTS idx_analytics_created_at ON analytics(created_at);
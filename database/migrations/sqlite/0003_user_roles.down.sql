@@ -0,0 +1,2 @@
+-- The original free-form role values are not restored
+SELECT 1;
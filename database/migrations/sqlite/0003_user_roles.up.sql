@@ -0,0 +1,4 @@
+-- users.role now drives authorization: admin, owner or viewer. Existing
+-- free-form values (job titles) belong to portfolio owners. SQLite cannot add
+-- a CHECK constraint in place, so the API validates roles instead.
+UPDATE users SET role = 'owner' WHERE role NOT IN ('admin', 'owner', 'viewer');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_contact_messages_status_created_at;
+
+ALTER TABLE contact_messages DROP COLUMN replied_at;
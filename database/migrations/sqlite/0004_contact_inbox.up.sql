@@ -0,0 +1,4 @@
+-- Contact inbox: track when a message was answered and list by status quickly
+ALTER TABLE contact_messages ADD COLUMN replied_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_contact_messages_status_created_at ON contact_messages(status, created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE contact_messages DROP COLUMN spam_reasons;
+ALTER TABLE contact_messages DROP COLUMN spam_score;
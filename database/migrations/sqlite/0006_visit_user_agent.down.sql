@@ -0,0 +1,2 @@
+ALTER TABLE analytics DROP COLUMN os;
+ALTER TABLE analytics DROP COLUMN browser_version;
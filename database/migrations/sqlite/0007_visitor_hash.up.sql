@@ -0,0 +1,12 @@
+-- Visitors are identified by a salted hash of IP address and user agent. The
+-- salt rotates daily and old salts are deleted, so hashes cannot be linked
+-- across days or reversed. Raw IP addresses are no longer stored.
+ALTER TABLE analytics ADD COLUMN visitor_id VARCHAR(64);
+UPDATE analytics SET ip_address = NULL WHERE ip_address IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_analytics_visitor_id ON analytics(visitor_id);
+
+CREATE TABLE IF NOT EXISTS visitor_salts (
+	day CHAR(10) PRIMARY KEY,
+	salt CHAR(64) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
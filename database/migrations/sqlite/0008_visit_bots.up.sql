@@ -0,0 +1,6 @@
+-- Visits from crawlers, monitors and excluded networks are kept but flagged
+-- so statistics can leave them out
+ALTER TABLE analytics ADD COLUMN is_bot BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE analytics ADD COLUMN bot_reason VARCHAR(20);
+UPDATE analytics SET is_bot = TRUE, bot_reason = 'user_agent' WHERE device_type = 'bot';
+CREATE INDEX IF NOT EXISTS idx_analytics_is_bot_created_at ON analytics(is_bot, created_at);
@@ -0,0 +1,4 @@
+-- Heartbeat and leave events record how long a page stayed open; sessions
+-- are derived from visits grouped by visitor_id
+ALTER TABLE analytics ADD COLUMN ended_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_analytics_visitor_id_created_at ON analytics(visitor_id, created_at);
@@ -0,0 +1,5 @@
+ALTER TABLE analytics DROP COLUMN utm_campaign;
+ALTER TABLE analytics DROP COLUMN utm_medium;
+ALTER TABLE analytics DROP COLUMN utm_source;
+ALTER TABLE analytics DROP COLUMN channel;
+ALTER TABLE analytics DROP COLUMN referrer_host;
@@ -0,0 +1,7 @@
+-- Where visits come from: the normalized referring host, its channel
+-- (search, social, email, direct, other) and the landing page's UTM parameters
+ALTER TABLE analytics ADD COLUMN referrer_host VARCHAR(255);
+ALTER TABLE analytics ADD COLUMN channel VARCHAR(20);
+ALTER TABLE analytics ADD COLUMN utm_source VARCHAR(255);
+ALTER TABLE analytics ADD COLUMN utm_medium VARCHAR(255);
+ALTER TABLE analytics ADD COLUMN utm_campaign VARCHAR(255);
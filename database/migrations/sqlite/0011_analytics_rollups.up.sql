@@ -0,0 +1,33 @@
+-- Hourly and daily aggregates of analytics rows per dimension value (total,
+-- page, entry/exit page, country, city, browser, os, device, channel,
+-- referrer, campaign). Rollups cover whole UTC days; the latest daily
+-- 'total' row marks how far visits have been aggregated.
+CREATE TABLE IF NOT EXISTS analytics_hourly (
+	period_start TIMESTAMP NOT NULL,
+	is_bot BOOLEAN NOT NULL DEFAULT 0,
+	dimension VARCHAR(20) NOT NULL,
+	value VARCHAR(600) NOT NULL DEFAULT '',
+	views INTEGER NOT NULL DEFAULT 0,
+	visitors INTEGER NOT NULL DEFAULT 0,
+	new_visitors INTEGER NOT NULL DEFAULT 0,
+	sessions INTEGER NOT NULL DEFAULT 0,
+	bounces INTEGER NOT NULL DEFAULT 0,
+	session_pages INTEGER NOT NULL DEFAULT 0,
+	session_seconds REAL NOT NULL DEFAULT 0,
+	PRIMARY KEY (period_start, is_bot, dimension, value)
+);
+
+CREATE TABLE IF NOT EXISTS analytics_daily (
+	period_start TIMESTAMP NOT NULL,
+	is_bot BOOLEAN NOT NULL DEFAULT 0,
+	dimension VARCHAR(20) NOT NULL,
+	value VARCHAR(600) NOT NULL DEFAULT '',
+	views INTEGER NOT NULL DEFAULT 0,
+	visitors INTEGER NOT NULL DEFAULT 0,
+	new_visitors INTEGER NOT NULL DEFAULT 0,
+	sessions INTEGER NOT NULL DEFAULT 0,
+	bounces INTEGER NOT NULL DEFAULT 0,
+	session_pages INTEGER NOT NULL DEFAULT 0,
+	session_seconds REAL NOT NULL DEFAULT 0,
+	PRIMARY KEY (period_start, is_bot, dimension, value)
+);
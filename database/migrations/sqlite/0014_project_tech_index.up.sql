@@ -0,0 +1,4 @@
+-- SQLite cannot index into JSON arrays; the tech filter scans tech_stack
+-- with json_each. This migration keeps the version numbers in step with
+-- Postgres.
+SELECT 1;
@@ -0,0 +1,12 @@
+-- Projects are addressed by slug as well as by ID. Slugs a project used
+-- before stay in project_slugs so that old links redirect. Existing projects
+-- get their slugs from the API on startup, which romanizes Korean titles.
+ALTER TABLE projects ADD COLUMN slug VARCHAR(80);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_slug ON projects(slug);
+
+CREATE TABLE IF NOT EXISTS project_slugs (
+	slug VARCHAR(80) PRIMARY KEY,
+	project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_project_slugs_project_id ON project_slugs(project_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_projects_publication;
+ALTER TABLE projects DROP COLUMN published_at;
+ALTER TABLE projects DROP COLUMN publish_at;
+ALTER TABLE projects DROP COLUMN publication;
@@ -0,0 +1,8 @@
+-- Projects move through a publication workflow separate from their status:
+-- draft, scheduled for publish_at, published and archived. Existing projects
+-- were all public, so they start out published.
+ALTER TABLE projects ADD COLUMN publication VARCHAR(20) NOT NULL DEFAULT 'published';
+ALTER TABLE projects ADD COLUMN publish_at TIMESTAMP;
+ALTER TABLE projects ADD COLUMN published_at TIMESTAMP;
+UPDATE projects SET published_at = created_at WHERE publication = 'published' AND published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_projects_publication ON projects(publication, publish_at);
@@ -0,0 +1,18 @@
+CREATE TABLE users_old (
+	id TEXT PRIMARY KEY,
+	email VARCHAR(255) UNIQUE NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	role VARCHAR(100) NOT NULL DEFAULT 'user',
+	avatar_url TEXT,
+	bio TEXT,
+	website TEXT,
+	location VARCHAR(255),
+	skills TEXT DEFAULT '[]',
+	is_public BOOLEAN DEFAULT 1,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO users_old (id, email, name, role, avatar_url, bio, website, location, skills, is_public, created_at, updated_at)
+	SELECT id, email, name, role, avatar_url, bio, website, location, skills, is_public, created_at, updated_at FROM users;
+DROP TABLE users;
+ALTER TABLE users_old RENAME TO users;
@@ -0,0 +1,23 @@
+-- 0003 could not add the role CHECK constraint or change the default role in
+-- place. SQLite changes constraints by rebuilding the table, which the
+-- migrator allows by running migrations with foreign keys off.
+UPDATE users SET role = 'owner' WHERE role NOT IN ('admin', 'owner', 'viewer');
+
+CREATE TABLE users_new (
+	id TEXT PRIMARY KEY,
+	email VARCHAR(255) UNIQUE NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	role VARCHAR(100) NOT NULL DEFAULT 'viewer' CHECK (role IN ('admin', 'owner', 'viewer')),
+	avatar_url TEXT,
+	bio TEXT,
+	website TEXT,
+	location VARCHAR(255),
+	skills TEXT DEFAULT '[]',
+	is_public BOOLEAN DEFAULT 1,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO users_new (id, email, name, role, avatar_url, bio, website, location, skills, is_public, created_at, updated_at)
+	SELECT id, email, name, role, avatar_url, bio, website, location, skills, is_public, created_at, updated_at FROM users;
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	_ "github.com/lib/pq"
+)
+
+// openPostgres connects to a plain Postgres or Supabase database
+func openPostgres(name, dsn, envVar string) (*sql.DB, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("%s environment variable is required", envVar)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", name, err)
+	}
+
+	// Test connection
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping %s: %v", name, err)
+	}
+
+	log.Printf("Successfully connected to %s", name)
+	return db, nil
+}
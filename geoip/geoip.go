@@ -0,0 +1,86 @@
+// Package geoip resolves visitor locations from a local MaxMind-format database
+package geoip
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Location is where an IP address is registered; fields are empty when unknown
+type Location struct {
+	// Country is the ISO 3166-1 alpha-2 code, e.g. "KR"
+	Country string
+	// City is the English city name
+	City string
+}
+
+// Locator resolves IP addresses to locations without network lookups
+type Locator interface {
+	Locate(ip string) Location
+	Close() error
+}
+
+// Nop returns a Locator that knows no locations
+func Nop() Locator {
+	return nopLocator{}
+}
+
+type nopLocator struct{}
+
+func (nopLocator) Locate(string) Location { return Location{} }
+
+func (nopLocator) Close() error { return nil }
+
+// DB is a Locator backed by a GeoLite2/GeoIP2 City or Country .mmdb file
+type DB struct {
+	reader *maxminddb.Reader
+}
+
+// record holds the fields read from City and Country databases alike
+type record struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+}
+
+// Open memory-maps the database at path
+func Open(path string) (*DB, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open GeoIP database: %w", err)
+	}
+	return &DB{reader: reader}, nil
+}
+
+// Locate looks up ip, returning an empty Location for private, malformed or
+// unlisted addresses
+func (d *DB) Locate(ip string) Location {
+	addr := net.ParseIP(ip)
+	if addr == nil || addr.IsPrivate() || addr.IsLoopback() || addr.IsUnspecified() {
+		return Location{}
+	}
+
+	var rec record
+	if err := d.reader.Lookup(addr, &rec); err != nil {
+		return Location{}
+	}
+
+	location := Location{Country: rec.Country.ISOCode, City: rec.City.Names["en"]}
+	if location.Country == "" {
+		location.Country = rec.RegisteredCountry.ISOCode
+	}
+	return location
+}
+
+// Close releases the database
+func (d *DB) Close() error {
+	return d.reader.Close()
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"portfolio-api/models"
+	"portfolio-api/repository"
+)
+
+// ListContactMessages retrieves contact form submissions for the inbox
+// @Summary List contact messages
+// @Description List contact messages, newest first unless sorted, with optional filters
+// @Tags contact
+// @Produce json
+// @Param status query string false "Comma separated statuses (unread, read, replied, archived, spam)"
+// @Param from query string false "Received at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Received before (RFC 3339, or through YYYY-MM-DD)"
+// @Param q query string false "Search name, email, subject and message"
+// @Param sort query string false "Comma separated fields, - for descending: name, email, subject, status, spam_score, created_at, read_at" default(-created_at)
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param page query int false "Page number, for offset pagination"
+// @Param cursor query string false "Cursor from a next or prev link"
+// @Param fields query string false "Comma separated fields to return"
+// @Success 200 {object} listing.Envelope
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /contact/messages [get]
+func (h *Handler) ListContactMessages(c *gin.Context) {
+	query, ok := parseList(c, repository.ContactList)
+	if !ok {
+		return
+	}
+
+	filter := repository.ContactFilter{Search: strings.TrimSpace(c.Query("q"))}
+
+	if statuses := c.Query("status"); statuses != "" {
+		for _, status := range strings.Split(statuses, ",") {
+			status = strings.TrimSpace(status)
+			if !models.IsContactStatus(status) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown status: " + status})
+				return
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	var err error
+	if filter.From, err = timeQuery(c, "from", false, time.UTC); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.To, err = timeQuery(c, "to", true, time.UTC); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.contacts.ListPage(c.Request.Context(), filter, query)
+	if err != nil {
+		respondPageError(c, err, "Failed to fetch contact messages")
+		return
+	}
+
+	respondList(c, page)
+}
+
+// GetContactMessage retrieves one contact message and marks it as read
+// @Summary Get a contact message
+// @Description Get a contact message; an unread message moves to "read"
+// @Tags contact
+// @Produce json
+// @Param id path string true "Message ID"
+// @Success 200 {object} models.ContactMessage
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /contact/messages/{id} [get]
+func (h *Handler) GetContactMessage(c *gin.Context) {
+	ctx := c.Request.Context()
+	message, err := h.contacts.Get(ctx, c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact message not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch contact message"})
+		return
+	}
+
+	if message.Status == models.ContactStatusUnread {
+		if err := message.TransitionTo(models.ContactStatusRead, time.Now().UTC()); err == nil {
+			if err := h.contacts.UpdateStatus(ctx, message); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark contact message as read"})
+				return
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, message)
+}
+
+// UpdateContactMessageStatus moves a contact message to a new status
+// @Summary Update a contact message's status
+// @Description Mark a message as unread, read, replied, archived or spam
+// @Tags contact
+// @Accept json
+// @Produce json
+// @Param id path string true "Message ID"
+// @Param status body models.UpdateContactStatusRequest true "New status"
+// @Success 200 {object} models.ContactMessage
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /contact/messages/{id} [patch]
+func (h *Handler) UpdateContactMessageStatus(c *gin.Context) {
+	var req models.UpdateContactStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	message, err := h.transitionContactMessage(c, c.Param("id"), req.Status)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact message not found"})
+		return
+	case errors.Is(err, models.ErrInvalidStatusTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update contact message"})
+		return
+	}
+
+	c.JSON(http.StatusOK, message)
+}
+
+// BulkUpdateContactStatus moves several contact messages to a new status
+// @Summary Bulk update contact message statuses
+// @Description Apply one status to up to 100 messages; messages that cannot make the transition are reported and left unchanged
+// @Tags contact
+// @Accept json
+// @Produce json
+// @Param request body models.BulkUpdateContactStatusRequest true "Message IDs and new status"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /contact/messages [patch]
+func (h *Handler) BulkUpdateContactStatus(c *gin.Context) {
+	var req models.BulkUpdateContactStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated := []string{}
+	failed := []gin.H{}
+	for _, id := range req.IDs {
+		_, err := h.transitionContactMessage(c, id, req.Status)
+		switch {
+		case err == nil:
+			updated = append(updated, id)
+		case errors.Is(err, repository.ErrNotFound):
+			failed = append(failed, gin.H{"id": id, "error": "Contact message not found"})
+		case errors.Is(err, models.ErrInvalidStatusTransition):
+			failed = append(failed, gin.H{"id": id, "error": err.Error()})
+		default:
+			failed = append(failed, gin.H{"id": id, "error": "Failed to update contact message"})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"updated": updated,
+		"failed":  failed,
+	})
+}
+
+// DeleteContactMessage deletes a contact message
+// @Summary Delete a contact message
+// @Description Permanently delete a contact message
+// @Tags contact
+// @Param id path string true "Message ID"
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /contact/messages/{id} [delete]
+func (h *Handler) DeleteContactMessage(c *gin.Context) {
+	err := h.contacts.Delete(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact message not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete contact message"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// transitionContactMessage loads a message, validates the status change and stores it
+func (h *Handler) transitionContactMessage(c *gin.Context, id, status string) (*models.ContactMessage, error) {
+	ctx := c.Request.Context()
+	message, err := h.contacts.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := message.TransitionTo(status, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	if err := h.contacts.UpdateStatus(ctx, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
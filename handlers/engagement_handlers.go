@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"portfolio-api/bots"
+	"portfolio-api/models"
+	"portfolio-api/repository"
+	"portfolio-api/stats"
+	"portfolio-api/useragent"
+	"portfolio-api/visitor"
+)
+
+// FollowProjectLink records a click on a project link and redirects to it
+// @Summary Follow a project link
+// @Description Redirect to a project's live site (live) or repository (github),
+// @Description counting the click for the project's click-through rate. Served at
+// @Description /p/{id}/{target}, outside /api/v1.
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Param target path string true "live or github"
+// @Success 302
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /p/{id}/{target} [get]
+func (h *Handler) FollowProjectLink(c *gin.Context) {
+	project, err := h.findProject(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project"})
+		return
+	}
+	if !canView(c, project) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	target := c.Param("target")
+	var link string
+	switch target {
+	case models.ClickTargetLive:
+		link = project.LiveURL
+	case models.ClickTargetGithub:
+		link = project.GithubURL
+	}
+	// Links saved before they were validated may not be web URLs
+	if !webURL(link) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project link not found"})
+		return
+	}
+
+	h.recordClick(c, project.ID, target)
+
+	// Every click must reach the server to be counted
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, link)
+}
+
+// recordClick stores a click unless the client opted out of tracking. Failures
+// are only logged so they never keep a visitor from the link.
+func (h *Handler) recordClick(c *gin.Context, projectID, target string) {
+	if visitor.OptedOut(c.Request) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	at := time.Now().UTC()
+	ip := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	visitorID, err := h.visitors.ID(ctx, at, ip, userAgent)
+	if err != nil {
+		log.Printf("Warning: failed to record click on project %s: %v", projectID, err)
+		return
+	}
+
+	click := &models.ProjectClick{
+		ProjectID: projectID,
+		Target:    target,
+		VisitorID: visitorID,
+		Referrer:  c.Request.Referer(),
+		CreatedAt: at,
+	}
+	click.BotReason = h.bots.Detect(bots.Client{
+		IP:        ip,
+		UserAgent: userAgent,
+		Info:      useragent.Parse(userAgent),
+		VisitorID: visitorID,
+	}, at)
+	click.IsBot = click.BotReason != ""
+
+	if err := h.clicks.Create(ctx, click); err != nil {
+		log.Printf("Warning: failed to record click on project %s: %v", projectID, err)
+	}
+}
+
+// projectEngagement computes the all-time engagement of the given projects,
+// or of every project, honouring the bots parameter. It responds with an
+// error when it cannot.
+func (h *Handler) projectEngagement(c *gin.Context, projectIDs ...string) (map[string]models.ProjectEngagement, bool) {
+	rows, ok := h.listRollups(c, time.Time{}, time.Now(), true, stats.DimProject)
+	if !ok {
+		return nil, false
+	}
+
+	// listRollups has validated the parameter
+	isBot, _ := botQuery(c)
+	counts, err := h.clicks.Count(c.Request.Context(), repository.ClickFilter{ProjectIDs: projectIDs, IsBot: isBot})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clicks"})
+		return nil, false
+	}
+
+	clicks := make(map[string]map[string]int)
+	for _, count := range counts {
+		if clicks[count.ProjectID] == nil {
+			clicks[count.ProjectID] = make(map[string]int)
+		}
+		clicks[count.ProjectID][count.Target] += count.Clicks
+	}
+	return stats.ComputeProjectEngagement(rows, clicks), true
+}
+
+// includeEngagement attaches each project's engagement when the include
+// parameter asks for it, and responds with an error when it cannot
+func (h *Handler) includeEngagement(c *gin.Context, projects []models.Project) bool {
+	if !includes(c, "engagement") || len(projects) == 0 {
+		return true
+	}
+
+	ids := make([]string, len(projects))
+	for i, project := range projects {
+		ids[i] = project.ID
+	}
+	engagement, ok := h.projectEngagement(c, ids...)
+	if !ok {
+		return false
+	}
+
+	for i := range projects {
+		e := engagement[projects[i].ID]
+		e.ProjectID = projects[i].ID
+		projects[i].Engagement = &e
+	}
+	return true
+}
+
+// rankEngagement lists the engagement of projects, most viewed first
+func rankEngagement(projects []models.Project, engagement map[string]models.ProjectEngagement) []models.ProjectEngagement {
+	ranked := make([]models.ProjectEngagement, 0, len(projects))
+	for _, project := range projects {
+		e := engagement[project.ID]
+		e.ProjectID = project.ID
+		e.Title = project.Title
+		ranked = append(ranked, e)
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Views != b.Views {
+			return a.Views > b.Views
+		}
+		if a.Clicks != b.Clicks {
+			return a.Clicks > b.Clicks
+		}
+		return a.Title < b.Title
+	})
+	return ranked
+}
+
+// includes reports whether the comma separated include parameter lists name
+func includes(c *gin.Context, name string) bool {
+	for _, value := range strings.Split(c.Query("include"), ",") {
+		if strings.TrimSpace(value) == name {
+			return true
+		}
+	}
+	return false
+}
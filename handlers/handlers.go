@@ -1,152 +1,380 @@
 package handlers
 
 import (
-	"math/rand"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"portfolio-api/database"
+	"portfolio-api/auth"
+	"portfolio-api/bots"
+	"portfolio-api/geoip"
+	"portfolio-api/live"
 	"portfolio-api/models"
+	"portfolio-api/notify"
+	"portfolio-api/referrer"
+	"portfolio-api/repository"
+	"portfolio-api/slug"
+	"portfolio-api/spam"
+	"portfolio-api/stats"
+	"portfolio-api/visitor"
 )
 
-// Handler function aliases for better organization
-var (
-	// Project handlers
-	GetProjects   = getProjects
-	GetProject    = getProject
-	CreateProject = createProject
-	UpdateProject = updateProject
-	DeleteProject = deleteProject
-
-	// Skill handlers
-	GetSkills   = getSkills
-	AddSkill    = addSkill
-	RemoveSkill = removeSkill
-
-	// Contact handlers
-	SubmitContactForm = submitContactForm
-
-	// Stats handlers
-	GetViewStats    = getViewStats
-	GetProjectStats = getProjectStats
-	RecordVisit     = recordVisit
-)
+// Handler serves the portfolio endpoints from the injected repositories
+type Handler struct {
+	users     repository.UserRepository
+	projects  repository.ProjectRepository
+	skills    repository.SkillRepository
+	contacts  repository.ContactRepository
+	visits    repository.VisitRepository
+	rollups   repository.RollupRepository
+	clicks    repository.ClickRepository
+	search    repository.SearchRepository
+	visitors  *visitor.Hasher
+	notifier  notify.Notifier
+	spam      *spam.Filter
+	geo       geoip.Locator
+	bots      *bots.Detector
+	referrers *referrer.Classifier
+	live      *live.Hub
+}
 
-// Mock data for projects
-var projects = []models.Project{
-	{
-		ID:          1,
-		Title:       "Portfolio Website",
-		Description: "A responsive portfolio website built with Flutter Web, featuring i18n support and GitHub Pages deployment",
-		TechStack:   []string{"Flutter", "Dart", "GitHub Actions", "GitHub Pages"},
-		Status:      "completed",
-		Featured:    true,
-		LiveURL:     "https://hyoukjoolee.github.io/portfolio",
-		GithubURL:   "https://github.com/hyoukjoolee/portfolio",
-		ImageURL:    "https://via.placeholder.com/600x400",
-		StartDate:   time.Now().Add(-time.Hour * 24 * 60),
-		EndDate:     timePtr(time.Now().Add(-time.Hour * 24 * 30)),
-		CreatedAt:   time.Now().Add(-time.Hour * 24 * 30),
-		UpdatedAt:   time.Now(),
-	},
-	{
-		ID:          2,
-		Title:       "Go REST API",
-		Description: "RESTful API server built with Go and Gin framework, featuring Swagger documentation and Docker deployment",
-		TechStack:   []string{"Go", "Gin", "Docker", "AWS", "Swagger"},
-		Status:      "in-progress",
-		Featured:    true,
-		GithubURL:   "https://github.com/hyoukjoolee/portfolio-api",
-		ImageURL:    "https://via.placeholder.com/600x400",
-		StartDate:   time.Now().Add(-time.Hour * 24 * 7),
-		CreatedAt:   time.Now().Add(-time.Hour * 24 * 7),
-		UpdatedAt:   time.Now(),
-	},
-}
-
-// Mock data for skills
-var skills = []models.Skill{
-	{ID: 1, Name: "Go", Category: "backend", Level: "expert", YearsExp: 3, Featured: true, Color: "#00ADD8"},
-	{ID: 2, Name: "JavaScript", Category: "frontend", Level: "expert", YearsExp: 5, Featured: true, Color: "#F7DF1E"},
-	{ID: 3, Name: "TypeScript", Category: "frontend", Level: "advanced", YearsExp: 3, Featured: true, Color: "#3178C6"},
-	{ID: 4, Name: "Flutter", Category: "mobile", Level: "advanced", YearsExp: 2, Featured: true, Color: "#02569B"},
-	{ID: 5, Name: "Docker", Category: "devops", Level: "advanced", YearsExp: 3, Featured: false, Color: "#2496ED"},
-	{ID: 6, Name: "AWS", Category: "cloud", Level: "intermediate", YearsExp: 2, Featured: false, Color: "#FF9900"},
-}
-
-// Mock data for contacts
-var contacts = []models.ContactMessage{}
-
-// Mock data for visits
-var visits = []models.Visit{}
-
-// Helper function to create time pointer
-func timePtr(t time.Time) *time.Time {
-	return &t
+// New creates a Handler backed by the given repositories, notifier, spam
+// filter, visitor locator, bot detector, referrer classifier and live hub
+func New(repos *repository.Repositories, notifier notify.Notifier, spamFilter *spam.Filter, geo geoip.Locator, botDetector *bots.Detector, referrers *referrer.Classifier, liveHub *live.Hub) *Handler {
+	return &Handler{
+		users:     repos.Users,
+		projects:  repos.Projects,
+		skills:    repos.Skills,
+		contacts:  repos.Contacts,
+		visits:    repos.Visits,
+		rollups:   repos.Rollups,
+		clicks:    repos.Clicks,
+		search:    repos.Search,
+		visitors:  visitor.NewHasher(repos.Salts),
+		notifier:  notifier,
+		spam:      spamFilter,
+		geo:       geo,
+		bots:      botDetector,
+		referrers: referrers,
+		live:      liveHub,
+	}
 }
 
-// Project handlers
-func getProjects(c *gin.Context) {
-	status := c.Query("status")
-	featured := c.Query("featured")
+// boolQuery parses an optional boolean query parameter
+func boolQuery(c *gin.Context, key string) *bool {
+	value, err := strconv.ParseBool(c.Query(key))
+	if err != nil {
+		return nil
+	}
+	return &value
+}
 
-	filteredProjects := projects
+// parseBoolQuery parses an optional boolean query parameter, rejecting
+// values strconv.ParseBool does not accept
+func parseBoolQuery(c *gin.Context, key string) (*bool, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: use true or false", key)
+	}
+	return &value, nil
+}
 
-	if status != "" {
-		var filtered []models.Project
-		for _, p := range projects {
-			if p.Status == status {
-				filtered = append(filtered, p)
-			}
+// listQuery splits a comma separated query parameter, dropping blanks and
+// repeats
+func listQuery(c *gin.Context, key string) []string {
+	var values []string
+	for _, value := range strings.Split(c.Query(key), ",") {
+		value = strings.TrimSpace(value)
+		if value != "" && !slices.Contains(values, value) {
+			values = append(values, value)
 		}
-		filteredProjects = filtered
 	}
+	return values
+}
+
+// timeQuery parses an optional RFC 3339 timestamp or YYYY-MM-DD date query
+// parameter; dates are midnight in loc. A bare date used as an exclusive
+// upper bound covers the whole day.
+func timeQuery(c *gin.Context, key string, upperBound bool, loc *time.Location) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: use RFC 3339 or YYYY-MM-DD", key)
+	}
+	if upperBound {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// authorizeOwner responds with 403 unless the caller may manage a resource
+// owned by ownerID
+func authorizeOwner(c *gin.Context, ownerID string) bool {
+	principal, ok := auth.PrincipalFrom(c)
+	if !ok || !principal.CanManage(ownerID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to modify this resource"})
+		return false
+	}
+	return true
+}
+
+// resolveOwner picks the owner of a new resource: the caller by default, or
+// the requested user when the caller is an admin. It responds with an error
+// and returns false when the request cannot be honoured.
+func (h *Handler) resolveOwner(c *gin.Context, requested string) (string, bool) {
+	principal, ok := auth.PrincipalFrom(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return "", false
+	}
+	if requested == "" || requested == principal.UserID {
+		return principal.UserID, true
+	}
+	if !principal.IsAdmin() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can create resources for other users"})
+		return "", false
+	}
+
+	_, err := h.users.Get(c.Request.Context(), requested)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "User not found"})
+		return "", false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return "", false
+	}
+	return requested, true
+}
+
+// webURL reports whether link is an absolute http or https URL, the only
+// links projects may send visitors to
+func webURL(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// checkProjectLinks requires the live and GitHub links of a project to be
+// web URLs when set, responding with 400 otherwise
+func checkProjectLinks(c *gin.Context, project *models.Project) bool {
+	if project.LiveURL != "" && !webURL(project.LiveURL) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "live_url must be an absolute http or https URL"})
+		return false
+	}
+	if project.GithubURL != "" && !webURL(project.GithubURL) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "github_url must be an absolute http or https URL"})
+		return false
+	}
+	return true
+}
 
-	if featured == "true" {
-		var filtered []models.Project
-		for _, p := range filteredProjects {
-			if p.Featured {
-				filtered = append(filtered, p)
+// canView reports whether the caller may see a project: published projects
+// are public, the others are shown to those who can manage them
+func canView(c *gin.Context, project *models.Project) bool {
+	if project.Publication == models.PublicationPublished {
+		return true
+	}
+	principal, ok := auth.PrincipalFrom(c)
+	return ok && principal.CanManage(project.UserID)
+}
+
+// setPublication moves a project to a publication state, keeping its current
+// one when state is empty. Scheduling needs a future publishAt, which only
+// scheduled projects may have; publishing stamps the first publication time.
+func setPublication(project *models.Project, state string, publishAt *time.Time, now time.Time) error {
+	if state == "" {
+		state = project.Publication
+	}
+	if publishAt != nil && state != models.PublicationScheduled {
+		return errors.New("publish_at is only allowed when publication is scheduled")
+	}
+
+	switch state {
+	case models.PublicationScheduled:
+		if publishAt == nil && project.Publication != models.PublicationScheduled {
+			return errors.New("publish_at is required to schedule a project")
+		}
+		if publishAt != nil {
+			if !publishAt.After(now) {
+				return errors.New("publish_at must be in the future")
 			}
+			at := publishAt.UTC()
+			project.PublishAt = &at
+		}
+	case models.PublicationPublished:
+		project.PublishAt = nil
+		if project.PublishedAt == nil {
+			project.PublishedAt = &now
 		}
-		filteredProjects = filtered
+	default:
+		project.PublishAt = nil
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  filteredProjects,
-		"count": len(filteredProjects),
-	})
+	project.Publication = state
+	return nil
 }
 
-func getProject(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
+// projectFilter reads the filters shared by the project lists, responding
+// with 400 when one is invalid. Unpublished projects are only listed to
+// those who can manage them.
+func projectFilter(c *gin.Context) (repository.ProjectFilter, bool) {
+	filter := repository.ProjectFilter{
+		Statuses:     listQuery(c, "status"),
+		TechStack:    listQuery(c, "tech"),
+		Search:       strings.TrimSpace(c.Query("q")),
+		Publications: listQuery(c, "publication"),
+	}
+
+	for _, state := range filter.Publications {
+		if !models.IsPublication(state) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "publication must be draft, scheduled, published or archived"})
+			return filter, false
+		}
+	}
+	if principal, ok := auth.PrincipalFrom(c); !ok {
+		filter.Visible = true
+	} else if !principal.IsAdmin() {
+		filter.Visible = true
+		filter.ViewerID = principal.UserID
+	}
+
+	switch c.DefaultQuery("tech_match", "any") {
+	case "any":
+	case "all":
+		filter.AllTech = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tech_match must be any or all"})
+		return filter, false
+	}
+
+	var err error
+	if filter.Featured, err = parseBoolQuery(c, "featured"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return filter, false
+	}
+	if filter.HasLiveURL, err = parseBoolQuery(c, "has_live_url"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return filter, false
+	}
+	if filter.StartedAfter, err = timeQuery(c, "started_after", false, time.UTC); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return filter, false
+	}
+	if filter.EndedBefore, err = timeQuery(c, "ended_before", true, time.UTC); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return filter, false
+	}
+	return filter, true
+}
+
+// Project handlers
+func (h *Handler) GetProjects(c *gin.Context) {
+	query, ok := parseList(c, repository.ProjectList)
+	if !ok {
+		return
+	}
+	filter, ok := projectFilter(c)
+	if !ok {
+		return
+	}
+	filter.UserID = c.Query("user_id")
+
+	page, err := h.projects.ListPage(c.Request.Context(), filter, query)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		respondPageError(c, err, "Failed to fetch projects")
+		return
+	}
+	if !h.includeEngagement(c, page.Items) {
 		return
 	}
 
-	for _, project := range projects {
-		if project.ID == id {
-			c.JSON(http.StatusOK, project)
-			return
+	respondList(c, page)
+}
+
+// invalidSlug explains the slug format to clients that send a bad one
+const invalidSlug = "slug must be lowercase letters and digits in hyphen separated words, at most 80 characters"
+
+// findProject looks a project up by ID or by slug, current or retired
+func (h *Handler) findProject(ctx context.Context, idOrSlug string) (*models.Project, error) {
+	if s := strings.ToLower(idOrSlug); slug.Valid(s) {
+		return h.projects.GetBySlug(ctx, s)
+	}
+	return h.projects.Get(ctx, strings.ToLower(idOrSlug))
+}
+
+func (h *Handler) GetProject(c *gin.Context) {
+	idOrSlug := c.Param("id")
+	project, err := h.findProject(c.Request.Context(), idOrSlug)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project"})
+		return
+	}
+	if !canView(c, project) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	// A retired slug moved permanently to the current one
+	if slug.Valid(strings.ToLower(idOrSlug)) && project.Slug != idOrSlug {
+		location := strings.TrimSuffix(c.Request.URL.Path, idOrSlug) + project.Slug
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
 		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
 	}
 
-	c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	projects := []models.Project{*project}
+	if !h.includeEngagement(c, projects) {
+		return
+	}
+
+	c.JSON(http.StatusOK, projects[0])
 }
 
-func createProject(c *gin.Context) {
+func (h *Handler) CreateProject(c *gin.Context) {
 	var req models.CreateProjectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	ownerID, ok := h.resolveOwner(c, req.UserID)
+	if !ok {
+		return
+	}
+
+	if req.Slug != "" && !slug.Valid(req.Slug) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidSlug})
+		return
+	}
+
 	newProject := models.Project{
-		ID:          len(projects) + 1,
+		UserID:      ownerID,
+		Slug:        req.Slug,
 		Title:       req.Title,
 		Description: req.Description,
 		TechStack:   req.TechStack,
@@ -157,131 +385,183 @@ func createProject(c *gin.Context) {
 		ImageURL:    req.ImageURL,
 		StartDate:   req.StartDate,
 		EndDate:     req.EndDate,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		Publication: models.PublicationDraft,
+	}
+	if !checkProjectLinks(c, &newProject) {
+		return
+	}
+	if err := setPublication(&newProject, req.Publication, req.PublishAt, time.Now().UTC()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	projects = append(projects, newProject)
-	c.JSON(http.StatusCreated, newProject)
-}
-
-func updateProject(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
+	err := h.projects.Create(c.Request.Context(), &newProject)
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug is already in use"})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project"})
 		return
 	}
 
+	c.JSON(http.StatusCreated, newProject)
+}
+
+func (h *Handler) UpdateProject(c *gin.Context) {
 	var req models.UpdateProjectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	for i, project := range projects {
-		if project.ID == id {
-			if req.Title != nil {
-				projects[i].Title = *req.Title
-			}
-			if req.Description != nil {
-				projects[i].Description = *req.Description
-			}
-			if req.TechStack != nil {
-				projects[i].TechStack = *req.TechStack
-			}
-			if req.Status != nil {
-				projects[i].Status = *req.Status
-			}
-			if req.Featured != nil {
-				projects[i].Featured = *req.Featured
-			}
-			if req.LiveURL != nil {
-				projects[i].LiveURL = *req.LiveURL
-			}
-			if req.GithubURL != nil {
-				projects[i].GithubURL = *req.GithubURL
-			}
-			if req.ImageURL != nil {
-				projects[i].ImageURL = *req.ImageURL
-			}
-			if req.StartDate != nil {
-				projects[i].StartDate = *req.StartDate
-			}
-			if req.EndDate != nil {
-				projects[i].EndDate = req.EndDate
-			}
-			projects[i].UpdatedAt = time.Now()
+	ctx := c.Request.Context()
+	project, err := h.projects.Get(ctx, c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project"})
+		return
+	}
+	if !authorizeOwner(c, project.UserID) {
+		return
+	}
 
-			c.JSON(http.StatusOK, projects[i])
+	// Update only provided fields
+	if req.Title != nil {
+		project.Title = *req.Title
+	}
+	if req.Slug != nil {
+		if !slug.Valid(*req.Slug) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": invalidSlug})
+			return
+		}
+		project.Slug = *req.Slug
+	}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+	if req.TechStack != nil {
+		project.TechStack = *req.TechStack
+	}
+	if req.Status != nil {
+		project.Status = *req.Status
+	}
+	if req.Featured != nil {
+		project.Featured = *req.Featured
+	}
+	if req.LiveURL != nil {
+		project.LiveURL = *req.LiveURL
+	}
+	if req.GithubURL != nil {
+		project.GithubURL = *req.GithubURL
+	}
+	if req.ImageURL != nil {
+		project.ImageURL = *req.ImageURL
+	}
+	if req.StartDate != nil {
+		project.StartDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		project.EndDate = req.EndDate
+	}
+	if !checkProjectLinks(c, project) {
+		return
+	}
+	if req.Publication != nil || req.PublishAt != nil {
+		var state string
+		if req.Publication != nil {
+			state = *req.Publication
+		}
+		if err := setPublication(project, state, req.PublishAt, time.Now().UTC()); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 	}
 
-	c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	err = h.projects.Update(ctx, project)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug is already in use"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
 }
 
-func deleteProject(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
+func (h *Handler) DeleteProject(c *gin.Context) {
+	ctx := c.Request.Context()
+	project, err := h.projects.Get(ctx, c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project"})
+		return
+	}
+	if !authorizeOwner(c, project.UserID) {
 		return
 	}
 
-	for i, project := range projects {
-		if project.ID == id {
-			projects = append(projects[:i], projects[i+1:]...)
-			c.Status(http.StatusNoContent)
-			return
-		}
+	err = h.projects.Delete(ctx, project.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
+		return
 	}
 
-	c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	c.Status(http.StatusNoContent)
 }
 
 // Skill handlers
-func getSkills(c *gin.Context) {
-	category := c.Query("category")
-	featured := c.Query("featured")
-
-	filteredSkills := skills
+func (h *Handler) GetSkills(c *gin.Context) {
+	query, ok := parseList(c, repository.SkillList)
+	if !ok {
+		return
+	}
 
-	if category != "" {
-		var filtered []models.Skill
-		for _, s := range skills {
-			if s.Category == category {
-				filtered = append(filtered, s)
-			}
-		}
-		filteredSkills = filtered
+	filter := repository.SkillFilter{
+		UserID:   c.Query("user_id"),
+		Category: c.Query("category"),
+		Featured: boolQuery(c, "featured"),
 	}
 
-	if featured == "true" {
-		var filtered []models.Skill
-		for _, s := range filteredSkills {
-			if s.Featured {
-				filtered = append(filtered, s)
-			}
-		}
-		filteredSkills = filtered
+	page, err := h.skills.ListPage(c.Request.Context(), filter, query)
+	if err != nil {
+		respondPageError(c, err, "Failed to fetch skills")
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  filteredSkills,
-		"count": len(filteredSkills),
-	})
+	respondList(c, page)
 }
 
-func addSkill(c *gin.Context) {
+func (h *Handler) AddSkill(c *gin.Context) {
 	var req models.AddSkillRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	ownerID, ok := h.resolveOwner(c, req.UserID)
+	if !ok {
+		return
+	}
+
 	newSkill := models.Skill{
-		ID:          len(skills) + 1,
+		UserID:      ownerID,
 		Name:        req.Name,
 		Category:    req.Category,
 		Level:       req.Level,
@@ -292,83 +572,346 @@ func addSkill(c *gin.Context) {
 		Description: req.Description,
 	}
 
-	skills = append(skills, newSkill)
+	if err := h.skills.Create(c.Request.Context(), &newSkill); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add skill"})
+		return
+	}
+
 	c.JSON(http.StatusCreated, newSkill)
 }
 
-func removeSkill(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
+func (h *Handler) RemoveSkill(c *gin.Context) {
+	ctx := c.Request.Context()
+	skill, err := h.skills.Get(ctx, c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skill ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch skill"})
+		return
+	}
+	if !authorizeOwner(c, skill.UserID) {
 		return
 	}
 
-	for i, skill := range skills {
-		if skill.ID == id {
-			skills = append(skills[:i], skills[i+1:]...)
-			c.Status(http.StatusNoContent)
-			return
-		}
+	err = h.skills.Delete(ctx, skill.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove skill"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
 
-	c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+// Contact handlers
+func (h *Handler) GetContactToken(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"token": h.spam.IssueToken()})
 }
 
-// Contact handler
-func submitContactForm(c *gin.Context) {
+func (h *Handler) SubmitContactForm(c *gin.Context) {
 	var req models.ContactFormRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	ctx := c.Request.Context()
+	submission := &spam.Submission{
+		Name:         req.Name,
+		Email:        req.Email,
+		Subject:      req.Subject,
+		Message:      req.Message,
+		Honeypot:     req.Website,
+		FormToken:    req.FormToken,
+		CaptchaToken: req.CaptchaToken,
+		IP:           c.ClientIP(),
+		UserAgent:    c.Request.UserAgent(),
+		ReceivedAt:   time.Now(),
+	}
+
+	verdict, err := h.spam.Evaluate(ctx, submission)
+	var rateLimited *spam.RateLimitError
+	switch {
+	case errors.As(err, &rateLimited):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(rateLimited.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many submissions, please try again later"})
+		return
+	case errors.Is(err, spam.ErrCaptchaFailed):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CAPTCHA verification failed"})
+		return
+	case err != nil:
+		log.Printf("Warning: spam check failed: %v", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify submission, please try again later"})
+		return
+	}
+
 	newContact := models.ContactMessage{
-		ID:        len(contacts) + 1,
-		Name:      req.Name,
-		Email:     req.Email,
-		Subject:   req.Subject,
-		Message:   req.Message,
-		Status:    "unread",
-		CreatedAt: time.Now(),
+		Name:        req.Name,
+		Email:       req.Email,
+		Subject:     req.Subject,
+		Message:     req.Message,
+		Status:      models.ContactStatusUnread,
+		IPAddress:   submission.IP,
+		UserAgent:   submission.UserAgent,
+		SpamScore:   verdict.Score,
+		SpamReasons: verdict.Reasons,
+	}
+	if verdict.Spam {
+		newContact.Status = models.ContactStatusSpam
 	}
 
-	contacts = append(contacts, newContact)
+	if err := h.contacts.Create(ctx, &newContact); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit contact form"})
+		return
+	}
 
+	// Emails are queued and sent in the background; spam is stored silently
+	if !verdict.Spam {
+		h.notifier.ContactSubmitted(newContact)
+	}
+
+	// Spam gets the same response so bots learn nothing
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Contact form submitted successfully",
 		"id":      newContact.ID,
 	})
 }
 
-// Stats handlers
-func getViewStats(c *gin.Context) {
-	// Generate some mock statistics
-	stats := models.ViewStats{
-		TotalViews:     1250 + rand.Intn(100),
-		UniqueVisitors: 950 + rand.Intn(50),
-		ViewsToday:     45 + rand.Intn(20),
-		ViewsThisWeek:  320 + rand.Intn(50),
-		ViewsThisMonth: 1100 + rand.Intn(100),
-		TopPages: []models.PageStat{
-			{Page: "/projects", Views: 450},
-			{Page: "/", Views: 320},
-			{Page: "/resume", Views: 280},
-			{Page: "/contact", Views: 200},
-		},
-		ViewsByCountry: []models.CountryStat{
-			{Country: "South Korea", Views: 680},
-			{Country: "United States", Views: 320},
-			{Country: "Japan", Views: 180},
-			{Country: "Others", Views: 70},
-		},
-		ViewsOverTime: generateTimeStats(),
+// GetViewStats reports views and visitors over time
+// @Summary Get view statistics
+// @Description Views, unique visitors, sessions, bounce rate and views over time, with the top pages, entry and exit pages, countries, cities, browsers, operating systems and devices. Served from the hourly and daily rollups.
+// @Tags stats
+// @Produce json
+// @Param from query string false "Start (RFC 3339 or YYYY-MM-DD); defaults to 30 days before to"
+// @Param to query string false "End, exclusive (RFC 3339 or YYYY-MM-DD, inclusive day); defaults to now"
+// @Param granularity query string false "Bucket size: hour, day, week or month" default(day)
+// @Param tz query string false "IANA time zone for buckets and bare dates" default(UTC)
+// @Param bots query string false "exclude, include or only" default(exclude)
+// @Success 200 {object} models.ViewStats
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /stats/views [get]
+func (h *Handler) GetViewStats(c *gin.Context) {
+	query, err := viewQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, stats)
+	rows, ok := h.listRollups(c, query.LoadFrom(), query.LoadTo(), query.UseDailyRollups())
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, stats.ComputeViewStats(rows, query))
+}
+
+// GetReferrerStats reports where visitors come from
+// @Summary Get referrer statistics
+// @Description Views and visitors by channel (search, social, email, direct, other) and referring host
+// @Tags stats
+// @Produce json
+// @Param from query string false "Start (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "End, exclusive (RFC 3339 or YYYY-MM-DD, inclusive day)"
+// @Param tz query string false "IANA time zone" default(UTC)
+// @Param bots query string false "exclude, include or only" default(exclude)
+// @Param limit query int false "Number of referrers (1-100)" default(10)
+// @Success 200 {object} models.ReferrerStats
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /stats/referrers [get]
+func (h *Handler) GetReferrerStats(c *gin.Context) {
+	query, ok := sourceQuery(c)
+	if !ok {
+		return
+	}
+
+	rows, ok := h.listRollups(c, query.From, query.To, query.UseDailyRollups())
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, stats.ComputeReferrerStats(rows, query))
 }
 
-func getProjectStats(c *gin.Context) {
+// GetCampaignStats reports visits by UTM campaign
+// @Summary Get campaign statistics
+// @Description Views and visitors by utm_source, utm_medium and utm_campaign
+// @Tags stats
+// @Produce json
+// @Param from query string false "Start (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "End, exclusive (RFC 3339 or YYYY-MM-DD, inclusive day)"
+// @Param tz query string false "IANA time zone" default(UTC)
+// @Param bots query string false "exclude, include or only" default(exclude)
+// @Param limit query int false "Number of campaigns (1-100)" default(10)
+// @Success 200 {object} models.CampaignStats
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /stats/campaigns [get]
+func (h *Handler) GetCampaignStats(c *gin.Context) {
+	query, ok := sourceQuery(c)
+	if !ok {
+		return
+	}
+
+	rows, ok := h.listRollups(c, query.From, query.To, query.UseDailyRollups())
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, stats.ComputeCampaignStats(rows, query))
+}
+
+// sourceQuery reads the period and limit of a referrer or campaign report,
+// responding with 400 when they are invalid
+func sourceQuery(c *gin.Context) (stats.ViewQuery, bool) {
+	query, err := viewQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return stats.ViewQuery{}, false
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return stats.ViewQuery{}, false
+		}
+		query.TopN = n
+	}
+	return query, true
+}
+
+// listRollups loads the hourly or daily rollups between from and to, of the
+// given dimensions or all, honouring the bots parameter, and responds with an
+// error when it cannot. Days the rollup job has not reached yet are rolled up
+// from the raw visits.
+func (h *Handler) listRollups(c *gin.Context, from, to time.Time, daily bool, dimensions ...string) ([]models.Rollup, bool) {
+	isBot, err := botQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	ctx := c.Request.Context()
+	watermark, err := h.rollups.Watermark(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch statistics"})
+		return nil, false
+	}
+
+	rows := []models.Rollup{}
+	if from.Before(watermark) {
+		filter := repository.RollupFilter{From: from, To: to, IsBot: isBot, Dimensions: dimensions}
+		if watermark.Before(to) {
+			filter.To = watermark
+		}
+		list := h.rollups.ListHourly
+		if daily {
+			list = h.rollups.ListDaily
+		}
+		if rows, err = list(ctx, filter); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch statistics"})
+			return nil, false
+		}
+	}
+
+	if watermark.Before(to) {
+		// Whole UTC days keep the first sightings of visitors exact
+		liveFrom := stats.Day.Truncate(from.UTC())
+		if liveFrom.Before(watermark) {
+			liveFrom = watermark
+		}
+		filter := repository.VisitFilter{From: liveFrom, To: to, IsBot: isBot}
+		visits, err := h.visits.List(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch visits"})
+			return nil, false
+		}
+
+		live := stats.RollupVisits(visits, h.referrers)
+		if daily {
+			live = stats.DailyRollups(live)
+		}
+		for _, row := range live {
+			if len(dimensions) == 0 || slices.Contains(dimensions, row.Dimension) {
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows, true
+}
+
+// viewQuery reads the tz, from, to and granularity parameters of a stats
+// request. The default period is the last 30 days in tz, including today.
+func viewQuery(c *gin.Context) (stats.ViewQuery, error) {
+	loc := time.UTC
+	if tz := c.Query("tz"); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return stats.ViewQuery{}, fmt.Errorf("invalid tz %q", tz)
+		}
+	}
+
+	granularity, err := stats.ParseGranularity(c.Query("granularity"))
+	if err != nil {
+		return stats.ViewQuery{}, err
+	}
+
+	now := time.Now().In(loc)
+	query := stats.ViewQuery{Now: now, Location: loc, Granularity: granularity, TopN: 10}
+
+	if query.From, err = timeQuery(c, "from", false, loc); err != nil {
+		return stats.ViewQuery{}, err
+	}
+	if query.To, err = timeQuery(c, "to", true, loc); err != nil {
+		return stats.ViewQuery{}, err
+	}
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = stats.Day.Truncate(query.To.In(loc)).AddDate(0, 0, -29)
+	}
+
+	if !query.From.Before(query.To) {
+		return stats.ViewQuery{}, errors.New("from must be before to")
+	}
+	if query.BucketCount() > stats.MaxBuckets {
+		return stats.ViewQuery{}, fmt.Errorf("period too long for %s granularity (max %d buckets)", granularity, stats.MaxBuckets)
+	}
+	return query, nil
+}
+
+// botQuery reads the bots parameter of a stats request: exclude (default),
+// include or only
+func botQuery(c *gin.Context) (*bool, error) {
+	switch c.DefaultQuery("bots", "exclude") {
+	case "exclude":
+		isBot := false
+		return &isBot, nil
+	case "include":
+		return nil, nil
+	case "only":
+		isBot := true
+		return &isBot, nil
+	default:
+		return nil, errors.New("invalid bots: use exclude, include or only")
+	}
+}
+
+func (h *Handler) GetProjectStats(c *gin.Context) {
+	projects, err := h.projects.List(c.Request.Context(), repository.ProjectFilter{Visible: true})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
+		return
+	}
+
 	completed := 0
 	featured := 0
 	techCount := make(map[string]int)
@@ -407,55 +950,19 @@ func getProjectStats(c *gin.Context) {
 		})
 	}
 
+	engagement, ok := h.projectEngagement(c)
+	if !ok {
+		return
+	}
+
 	stats := models.ProjectStats{
 		TotalProjects:     len(projects),
 		CompletedProjects: completed,
 		FeaturedProjects:  featured,
 		TechStackStats:    techStats,
 		ProjectsByStatus:  statusStats,
+		Engagement:        rankEngagement(projects, engagement),
 	}
 
 	c.JSON(http.StatusOK, stats)
 }
-
-func recordVisit(c *gin.Context) {
-	var req models.VisitRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	newVisit := models.Visit{
-		ID:        len(visits) + 1,
-		Page:      req.Page,
-		UserAgent: req.UserAgent,
-		Country:   req.Country,
-		Referrer:  req.Referrer,
-		IP:        c.ClientIP(),
-		CreatedAt: time.Now(),
-	}
-
-	visits = append(visits, newVisit)
-
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Visit recorded successfully",
-		"id":      newVisit.ID,
-	})
-}
-
-// Helper function to generate time-based statistics
-func generateTimeStats() []models.TimeStat {
-	var stats []models.TimeStat
-	now := time.Now()
-
-	for i := 6; i >= 0; i-- {
-		date := now.AddDate(0, 0, -i)
-		views := 40 + rand.Intn(60)
-		stats = append(stats, models.TimeStat{
-			Date:  date.Format("2006-01-02"),
-			Views: views,
-		})
-	}
-
-	return stats
-}
\ No newline at end of file
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"portfolio-api/listing"
+)
+
+// parseList reads the limit, page, cursor, sort and fields parameters of a
+// list request, responding with 400 when they are invalid
+func parseList[T any](c *gin.Context, resource listing.Resource[T]) (listing.Query, bool) {
+	query, err := listing.Parse(c.Request.URL.Query(), resource)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return listing.Query{}, false
+	}
+	return query, true
+}
+
+// respondPageError answers a failed repository page fetch, with 400 when the
+// cursor cannot be compared with the stored values
+func respondPageError(c *gin.Context, err error, message string) {
+	if errors.Is(err, listing.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// respondList writes a page of a list in the list envelope
+func respondList[T any](c *gin.Context, page listing.Page[T]) {
+	envelope, err := page.Envelope(c.Request.URL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render list"})
+		return
+	}
+	c.JSON(http.StatusOK, envelope)
+}
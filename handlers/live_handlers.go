@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"portfolio-api/models"
+)
+
+// liveActivityInterval is how often the live feed repeats the active visitor
+// count; it also keeps idle connections open through proxies
+const liveActivityInterval = 15 * time.Second
+
+// StreamLiveVisits streams visits as they are recorded
+// @Summary Live visitor feed
+// @Description Server-Sent Events stream of recorded visits ("visit" events with
+// @Description page, location, device and referrer host, never the IP address) and
+// @Description the number of visitors active in the last few minutes ("active" events,
+// @Description sent on connect, after each visit and every 15 seconds). Bot visits
+// @Description are left out. Clients that fall behind are disconnected and should reconnect.
+// @Tags stats
+// @Produce text/event-stream
+// @Success 200 {object} models.LiveVisit
+// @Failure 401 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /stats/live [get]
+func (h *Handler) StreamLiveVisits(c *gin.Context) {
+	sub, ok := h.live.Subscribe()
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live feed is shutting down"})
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Cache-Control", "no-cache")
+	// Keep nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(liveActivityInterval)
+	defer ticker.Stop()
+
+	c.SSEvent("active", h.liveActivity())
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case visit, ok := <-sub.Events():
+			if !ok {
+				return false
+			}
+			c.SSEvent("visit", visit)
+			c.SSEvent("active", h.liveActivity())
+		case <-ticker.C:
+			c.SSEvent("active", h.liveActivity())
+		}
+		return true
+	})
+}
+
+// liveActivity returns the current active visitor count
+func (h *Handler) liveActivity() models.LiveActivity {
+	now := time.Now().UTC()
+	return models.LiveActivity{ActiveVisitors: h.live.ActiveVisitors(now), At: now}
+}
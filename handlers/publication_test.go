@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"portfolio-api/models"
+)
+
+func TestSetPublication(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	later := now.Add(2 * time.Hour)
+	firstPublished := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name            string
+		project         models.Project
+		state           string
+		publishAt       *time.Time
+		wantErr         string
+		wantState       string
+		wantPublishAt   *time.Time
+		wantPublishedAt *time.Time
+	}{
+		{
+			name:      "empty state keeps a draft",
+			project:   models.Project{Publication: models.PublicationDraft},
+			wantState: models.PublicationDraft,
+		},
+		{
+			name:            "publishing stamps the publication time",
+			project:         models.Project{Publication: models.PublicationDraft},
+			state:           models.PublicationPublished,
+			wantState:       models.PublicationPublished,
+			wantPublishedAt: &now,
+		},
+		{
+			name:            "republishing keeps the first publication time",
+			project:         models.Project{Publication: models.PublicationArchived, PublishedAt: &firstPublished},
+			state:           models.PublicationPublished,
+			wantState:       models.PublicationPublished,
+			wantPublishedAt: &firstPublished,
+		},
+		{
+			name:          "scheduling",
+			project:       models.Project{Publication: models.PublicationDraft},
+			state:         models.PublicationScheduled,
+			publishAt:     &future,
+			wantState:     models.PublicationScheduled,
+			wantPublishAt: &future,
+		},
+		{
+			name:          "rescheduling without a state",
+			project:       models.Project{Publication: models.PublicationScheduled, PublishAt: &future},
+			publishAt:     &later,
+			wantState:     models.PublicationScheduled,
+			wantPublishAt: &later,
+		},
+		{
+			name:          "scheduled project keeps its time",
+			project:       models.Project{Publication: models.PublicationScheduled, PublishAt: &future},
+			state:         models.PublicationScheduled,
+			wantState:     models.PublicationScheduled,
+			wantPublishAt: &future,
+		},
+		{
+			name:            "publishing a scheduled project early",
+			project:         models.Project{Publication: models.PublicationScheduled, PublishAt: &future},
+			state:           models.PublicationPublished,
+			wantState:       models.PublicationPublished,
+			wantPublishedAt: &now,
+		},
+		{
+			name:      "unscheduling clears the time",
+			project:   models.Project{Publication: models.PublicationScheduled, PublishAt: &future},
+			state:     models.PublicationDraft,
+			wantState: models.PublicationDraft,
+		},
+		{
+			name:    "scheduling without a time",
+			project: models.Project{Publication: models.PublicationDraft},
+			state:   models.PublicationScheduled,
+			wantErr: "publish_at is required to schedule a project",
+		},
+		{
+			name:      "scheduling in the past",
+			project:   models.Project{Publication: models.PublicationDraft},
+			state:     models.PublicationScheduled,
+			publishAt: &past,
+			wantErr:   "publish_at must be in the future",
+		},
+		{
+			name:      "publish time on a published project",
+			project:   models.Project{Publication: models.PublicationPublished, PublishedAt: &firstPublished},
+			publishAt: &future,
+			wantErr:   "publish_at is only allowed when publication is scheduled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := tt.project
+			err := setPublication(&project, tt.state, tt.publishAt, now)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if project.Publication != tt.project.Publication {
+					t.Errorf("state changed to %q on a rejected transition", project.Publication)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if project.Publication != tt.wantState {
+				t.Errorf("state = %q, want %q", project.Publication, tt.wantState)
+			}
+			if !sameTime(project.PublishAt, tt.wantPublishAt) {
+				t.Errorf("publish_at = %v, want %v", project.PublishAt, tt.wantPublishAt)
+			}
+			if !sameTime(project.PublishedAt, tt.wantPublishedAt) {
+				t.Errorf("published_at = %v, want %v", project.PublishedAt, tt.wantPublishedAt)
+			}
+		})
+	}
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"portfolio-api/auth"
+	"portfolio-api/models"
+	"portfolio-api/repository"
+	"portfolio-api/search"
+)
+
+// Search finds projects, skills and public user profiles matching a query.
+// Private users' profiles, projects and skills only show up for themselves
+// and admins, as on /users/:id.
+// @Summary Search the portfolio
+// @Description Full-text search over project titles, descriptions and tech stacks, skill names, categories and descriptions, and public profiles; private users also find their own. Every word must start a word of a match, so partial words match while typing. Results come best first with an HTML-escaped snippet marking the matches in <mark> tags.
+// @Tags search
+// @Produce json
+// @Param q query string true "Search words"
+// @Param type query string false "Comma separated result types (project, skill, user)"
+// @Param sort query string false "Comma separated fields, - for descending: rank, type, title" default(-rank,title)
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param page query int false "Page number, for offset pagination"
+// @Param cursor query string false "Cursor from a next or prev link"
+// @Param fields query string false "Comma separated fields to return"
+// @Success 200 {object} listing.Envelope
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /search [get]
+func (h *Handler) Search(c *gin.Context) {
+	query, ok := parseList(c, repository.SearchList)
+	if !ok {
+		return
+	}
+
+	q := strings.TrimSpace(c.Query("q"))
+	if len(q) > search.MaxQueryLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("q must be at most %d characters", search.MaxQueryLength)})
+		return
+	}
+	filter := repository.SearchQuery{Terms: search.Terms(q)}
+	if len(filter.Terms) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q must contain a word to search for"})
+		return
+	}
+
+	if principal, ok := auth.PrincipalFrom(c); !ok {
+		filter.Visible = true
+	} else if !principal.IsAdmin() {
+		filter.Visible = true
+		filter.ViewerID = principal.UserID
+	}
+
+	if types := c.Query("type"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			t = strings.TrimSpace(t)
+			if !slices.Contains(models.SearchTypes, t) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown type: " + t})
+				return
+			}
+			filter.Types = append(filter.Types, t)
+		}
+	}
+
+	page, err := h.search.SearchPage(c.Request.Context(), filter, query)
+	if err != nil {
+		respondPageError(c, err, "Failed to search")
+		return
+	}
+
+	respondList(c, page)
+}
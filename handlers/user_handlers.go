@@ -1,119 +1,200 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"portfolio-api/database"
+	"portfolio-api/auth"
+	"portfolio-api/listing"
 	"portfolio-api/models"
+	"portfolio-api/repository"
 )
 
-// GetUsers retrieves all users from Supabase
+// GetUsers retrieves all users
 // @Summary Get all users
-// @Description Get list of all users with optional filtering
+// @Description Get list of all users with optional filtering. Only admins see private profiles.
 // @Tags users
 // @Produce json
 // @Param is_public query boolean false "Filter by public profile"
-// @Success 200 {object} map[string]interface{}
+// @Param sort query string false "Comma separated fields, - for descending: name, email, role, created_at, updated_at" default(-created_at)
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param page query int false "Page number, for offset pagination"
+// @Param cursor query string false "Cursor from a next or prev link"
+// @Param fields query string false "Comma separated fields to return"
+// @Success 200 {object} listing.Envelope
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /users [get]
-func GetUsers(c *gin.Context) {
-	isPublic := c.Query("is_public")
-
-	query := "SELECT id, email, name, role, avatar_url, bio, website, location, skills, is_public, created_at, updated_at FROM users WHERE 1=1"
-	args := []interface{}{}
-
-	if isPublic == "true" {
-		query += " AND is_public = $1"
-		args = append(args, true)
-	}
-
-	query += " ORDER BY created_at DESC"
-
-	rows, err := database.SupabaseDB.Query(query, args...)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+func (h *Handler) GetUsers(c *gin.Context) {
+	query, ok := parseList(c, repository.UserList)
+	if !ok {
 		return
 	}
-	defer rows.Close()
-
-	var users []models.User
-	for rows.Next() {
-		var user models.User
-		var skills string
 
-		err := rows.Scan(
-			&user.ID,
-			&user.Email,
-			&user.Name,
-			&user.Role,
-			&user.AvatarURL,
-			&user.Bio,
-			&user.Website,
-			&user.Location,
-			&skills,
-			&user.IsPublic,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
-		if err != nil {
-			continue
+	filter := repository.UserFilter{
+		IsPublic: boolQuery(c, "is_public"),
+	}
+	if principal, ok := auth.PrincipalFrom(c); !ok || !principal.IsAdmin() {
+		if filter.IsPublic != nil && !*filter.IsPublic {
+			respondList(c, listing.Paginate([]models.User{}, query, repository.UserList))
+			return
 		}
+		public := true
+		filter.IsPublic = &public
+	}
 
-		// Parse skills JSON array - simplified for now
-		user.Skills = []string{}
-		users = append(users, user)
+	page, err := h.users.ListPage(c.Request.Context(), filter, query)
+	if err != nil {
+		respondPageError(c, err, "Failed to fetch users")
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  users,
-		"count": len(users),
-	})
+	respondList(c, page)
 }
 
 // GetUserByID retrieves a specific user by ID
 // @Summary Get user by ID
-// @Description Get a specific user by their ID
+// @Description Get a specific user by their ID. Private profiles are only shown to their owner and admins.
 // @Tags users
 // @Produce json
 // @Param id path string true "User ID"
 // @Success 200 {object} models.User
-// @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
 // @Router /users/{id} [get]
-func GetUserByID(c *gin.Context) {
-	id := c.Param("id")
+func (h *Handler) GetUserByID(c *gin.Context) {
+	user, ok := h.findVisibleUser(c)
+	if !ok {
+		return
+	}
 
-	query := "SELECT id, email, name, role, avatar_url, bio, website, location, skills, is_public, created_at, updated_at FROM users WHERE id = $1"
+	c.JSON(http.StatusOK, user)
+}
 
-	var user models.User
-	var skills string
+// GetUserProjects retrieves the projects owned by a user
+// @Summary Get a user's projects
+// @Description Get the projects owned by a specific user. Private users' projects are only listed to their owner and admins.
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Param status query string false "Comma separated statuses"
+// @Param featured query boolean false "Filter by featured flag"
+// @Param tech query string false "Comma separated technologies, as spelled in tech_stack"
+// @Param tech_match query string false "any or all of the technologies" default(any)
+// @Param started_after query string false "Started on or after (RFC 3339 or YYYY-MM-DD)"
+// @Param ended_before query string false "Ended before (RFC 3339, or through YYYY-MM-DD)"
+// @Param has_live_url query boolean false "Filter by having a live site"
+// @Param q query string false "Search title and description"
+// @Param publication query string false "Comma separated publication states; unpublished projects are listed to their owners only"
+// @Param include query string false "engagement adds views, clicks and CTR"
+// @Param sort query string false "Comma separated fields, - for descending: title, status, featured, start_date, end_date, created_at, updated_at, publish_at, published_at" default(-created_at)
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param page query int false "Page number, for offset pagination"
+// @Param cursor query string false "Cursor from a next or prev link"
+// @Param fields query string false "Comma separated fields to return"
+// @Success 200 {object} listing.Envelope
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/projects [get]
+func (h *Handler) GetUserProjects(c *gin.Context) {
+	query, ok := parseList(c, repository.ProjectList)
+	if !ok {
+		return
+	}
+	filter, ok := projectFilter(c)
+	if !ok {
+		return
+	}
+	user, ok := h.findVisibleUser(c)
+	if !ok {
+		return
+	}
+	filter.UserID = user.ID
 
-	err := database.SupabaseDB.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Name,
-		&user.Role,
-		&user.AvatarURL,
-		&user.Bio,
-		&user.Website,
-		&user.Location,
-		&skills,
-		&user.IsPublic,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	page, err := h.projects.ListPage(c.Request.Context(), filter, query)
+	if err != nil {
+		respondPageError(c, err, "Failed to fetch projects")
+		return
+	}
+	if !h.includeEngagement(c, page.Items) {
+		return
+	}
 
+	respondList(c, page)
+}
+
+// GetUserSkills retrieves the skills owned by a user
+// @Summary Get a user's skills
+// @Description Get the skills owned by a specific user. Private users' skills are only listed to their owner and admins.
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Param category query string false "Filter by category"
+// @Param featured query boolean false "Filter by featured flag"
+// @Param sort query string false "Comma separated fields, - for descending: name, category, level, years_exp, featured" default(-featured,-years_exp,name)
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param page query int false "Page number, for offset pagination"
+// @Param cursor query string false "Cursor from a next or prev link"
+// @Param fields query string false "Comma separated fields to return"
+// @Success 200 {object} listing.Envelope
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/skills [get]
+func (h *Handler) GetUserSkills(c *gin.Context) {
+	query, ok := parseList(c, repository.SkillList)
+	if !ok {
+		return
+	}
+	user, ok := h.findVisibleUser(c)
+	if !ok {
+		return
+	}
+
+	filter := repository.SkillFilter{
+		UserID:   user.ID,
+		Category: c.Query("category"),
+		Featured: boolQuery(c, "featured"),
+	}
+
+	page, err := h.skills.ListPage(c.Request.Context(), filter, query)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondPageError(c, err, "Failed to fetch skills")
 		return
 	}
 
-	// Parse skills JSON array - simplified for now
-	user.Skills = []string{}
+	respondList(c, page)
+}
 
-	c.JSON(http.StatusOK, user)
+// findUser loads the user named by the :id path parameter, responding with
+// 404 or 500 when it cannot
+func (h *Handler) findUser(c *gin.Context) (*models.User, bool) {
+	user, err := h.users.Get(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return nil, false
+	}
+	return user, true
+}
+
+// findVisibleUser is findUser for reads: a private user is only found by
+// their owner and admins, so others cannot tell it exists
+func (h *Handler) findVisibleUser(c *gin.Context) (*models.User, bool) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return nil, false
+	}
+	if principal, ok := auth.PrincipalFrom(c); !user.IsPublic && (!ok || !principal.CanManage(user.ID)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return nil, false
+	}
+	return user, true
 }
 
 // CreateUser creates a new user
@@ -125,50 +206,31 @@ func GetUserByID(c *gin.Context) {
 // @Param user body models.CreateUserRequest true "User data"
 // @Success 201 {object} models.User
 // @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
 // @Router /users [post]
-func CreateUser(c *gin.Context) {
+func (h *Handler) CreateUser(c *gin.Context) {
 	var req models.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	query := `
-		INSERT INTO users (email, name, role, avatar_url, bio, website, location, skills, is_public)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING id, created_at, updated_at`
-
-	var user models.User
-	err := database.SupabaseDB.QueryRow(
-		query,
-		req.Email,
-		req.Name,
-		req.Role,
-		req.AvatarURL,
-		req.Bio,
-		req.Website,
-		req.Location,
-		"[]", // Empty skills array for now
-		req.IsPublic,
-	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	user := req.ToUser()
 
+	err := h.users.Create(c.Request.Context(), &user)
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "A user with this email already exists"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
 		return
 	}
 
-	// Fill in the rest of the user data
-	user.Email = req.Email
-	user.Name = req.Name
-	user.Role = req.Role
-	user.AvatarURL = req.AvatarURL
-	user.Bio = req.Bio
-	user.Website = req.Website
-	user.Location = req.Location
-	user.Skills = []string{}
-	user.IsPublic = req.IsPublic
-
 	c.JSON(http.StatusCreated, user)
 }
 
@@ -182,88 +244,57 @@ func CreateUser(c *gin.Context) {
 // @Param user body models.UpdateUserRequest true "User update data"
 // @Success 200 {object} models.User
 // @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
 // @Router /users/{id} [put]
-func UpdateUser(c *gin.Context) {
-	id := c.Param("id")
-
+func (h *Handler) UpdateUser(c *gin.Context) {
 	var req models.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Build dynamic update query
-	query := "UPDATE users SET updated_at = NOW()"
-	args := []interface{}{}
-	argIndex := 1
-
-	if req.Name != nil {
-		query += ", name = $" + strconv.Itoa(argIndex)
-		args = append(args, *req.Name)
-		argIndex++
-	}
-	if req.Role != nil {
-		query += ", role = $" + strconv.Itoa(argIndex)
-		args = append(args, *req.Role)
-		argIndex++
-	}
-	if req.AvatarURL != nil {
-		query += ", avatar_url = $" + strconv.Itoa(argIndex)
-		args = append(args, *req.AvatarURL)
-		argIndex++
-	}
-	if req.Bio != nil {
-		query += ", bio = $" + strconv.Itoa(argIndex)
-		args = append(args, *req.Bio)
-		argIndex++
+	ctx := c.Request.Context()
+	user, err := h.users.Get(ctx, c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
 	}
-	if req.Website != nil {
-		query += ", website = $" + strconv.Itoa(argIndex)
-		args = append(args, *req.Website)
-		argIndex++
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
 	}
-	if req.Location != nil {
-		query += ", location = $" + strconv.Itoa(argIndex)
-		args = append(args, *req.Location)
-		argIndex++
+
+	// Users edit their own profile; only admins edit others or change roles
+	principal, _ := auth.PrincipalFrom(c)
+	if principal == nil || (!principal.IsAdmin() && principal.UserID != user.ID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only update your own profile"})
+		return
 	}
-	if req.IsPublic != nil {
-		query += ", is_public = $" + strconv.Itoa(argIndex)
-		args = append(args, *req.IsPublic)
-		argIndex++
+	if req.Role != nil && *req.Role != user.Role && !principal.IsAdmin() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can change roles"})
+		return
 	}
 
-	query += " WHERE id = $" + strconv.Itoa(argIndex) + " RETURNING id, email, name, role, avatar_url, bio, website, location, skills, is_public, created_at, updated_at"
-	args = append(args, id)
-
-	var user models.User
-	var skills string
+	req.ApplyTo(user)
 
-	err := database.SupabaseDB.QueryRow(query, args...).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Name,
-		&user.Role,
-		&user.AvatarURL,
-		&user.Bio,
-		&user.Website,
-		&user.Location,
-		&skills,
-		&user.IsPublic,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
-	if err != nil {
+	err = h.users.Update(ctx, user)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
+	case errors.Is(err, repository.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "A user with this email already exists"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
 	}
 
-	// Parse skills JSON array - simplified for now
-	user.Skills = []string{}
-
 	c.JSON(http.StatusOK, user)
 }
 
@@ -273,24 +304,22 @@ func UpdateUser(c *gin.Context) {
 // @Tags users
 // @Param id path string true "User ID"
 // @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
 // @Router /users/{id} [delete]
-func DeleteUser(c *gin.Context) {
-	id := c.Param("id")
-
-	query := "DELETE FROM users WHERE id = $1"
-	result, err := database.SupabaseDB.Exec(query, id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+func (h *Handler) DeleteUser(c *gin.Context) {
+	err := h.users.Delete(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
 		return
 	}
 
 	c.Status(http.StatusNoContent)
-}
\ No newline at end of file
+}
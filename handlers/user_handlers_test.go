@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"portfolio-api/auth"
+	"portfolio-api/models"
+)
+
+func TestPrivateUserVisibility(t *testing.T) {
+	h, repos := newTestHandler(t)
+	ctx := context.Background()
+
+	private := models.User{Name: "Private", Email: "private@example.com", Role: models.RoleOwner}
+	if err := repos.Users.Create(ctx, &private); err != nil {
+		t.Fatal(err)
+	}
+	project := models.Project{UserID: private.ID, Title: "Hidden", Description: "d", Status: "completed", Publication: models.PublicationPublished}
+	if err := repos.Projects.Create(ctx, &project); err != nil {
+		t.Fatal(err)
+	}
+	skill := models.Skill{UserID: private.ID, Name: "Go", Category: "backend", Level: "expert"}
+	if err := repos.Skills.Create(ctx, &skill); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		want      int
+	}{
+		{name: "anonymous", want: http.StatusNotFound},
+		{name: "another owner", principal: &auth.Principal{Role: models.RoleOwner, UserID: "someone-else"}, want: http.StatusNotFound},
+		{name: "viewer", principal: &auth.Principal{Role: models.RoleViewer, UserID: private.ID}, want: http.StatusNotFound},
+		{name: "owner", principal: &auth.Principal{Role: models.RoleOwner, UserID: private.ID}, want: http.StatusOK},
+		{name: "admin", principal: &auth.Principal{Role: models.RoleAdmin}, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			if tt.principal != nil {
+				auth.SetPrincipal(c, tt.principal)
+			}
+		})
+		router.GET("/users/:id", h.GetUserByID)
+		router.GET("/users/:id/projects", h.GetUserProjects)
+		router.GET("/users/:id/skills", h.GetUserSkills)
+
+		for _, path := range []string{"/users/" + private.ID, "/users/" + private.ID + "/projects", "/users/" + private.ID + "/skills"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			if w.Code != tt.want {
+				t.Errorf("%s: GET %s = %d, want %d", tt.name, path, w.Code, tt.want)
+			}
+		}
+	}
+}
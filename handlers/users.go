@@ -1,196 +0,0 @@
-package handlers
-
-import (
-	"net/http"
-	"strconv"
-	"time"
-
-	"github.com/gin-gonic/gin"
-	"portfolio-api/models"
-)
-
-// Mock data for demonstration
-var users = []models.User{
-	{
-		ID:        1,
-		Name:      "이혁주",
-		Email:     "hyoukjoo@example.com",
-		Role:      "Backend Developer",
-		Avatar:    "https://avatars.githubusercontent.com/u/12345678",
-		Bio:       "Backend engineer specializing in Go, microservices, and cloud architecture",
-		Website:   "https://hyoukjoolee.github.io/portfolio",
-		Location:  "Seoul, Korea",
-		Skills:    []string{"Go", "JavaScript", "TypeScript", "Docker", "AWS", "PostgreSQL"},
-		CreatedAt: time.Now().Add(-time.Hour * 24 * 365),
-		UpdatedAt: time.Now(),
-	},
-}
-
-// @Summary Get all users
-// @Description Get a list of all users
-// @Tags users
-// @Accept json
-// @Produce json
-// @Success 200 {array} models.User
-// @Failure 500 {object} map[string]string
-// @Router /api/v1/users [get]
-func GetUsers(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"data":  users,
-		"count": len(users),
-	})
-}
-
-// @Summary Create a new user
-// @Description Create a new user with the provided information
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param user body models.CreateUserRequest true "User data"
-// @Success 201 {object} models.User
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /api/v1/users [post]
-func CreateUser(c *gin.Context) {
-	var req models.CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	newUser := models.User{
-		ID:        len(users) + 1,
-		Name:      req.Name,
-		Email:     req.Email,
-		Role:      req.Role,
-		Avatar:    req.Avatar,
-		Bio:       req.Bio,
-		Website:   req.Website,
-		Location:  req.Location,
-		Skills:    req.Skills,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	users = append(users, newUser)
-	c.JSON(http.StatusCreated, newUser)
-}
-
-// @Summary Get user by ID
-// @Description Get a specific user by their ID
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param id path int true "User ID"
-// @Success 200 {object} models.User
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Router /api/v1/users/{id} [get]
-func GetUserByID(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
-		return
-	}
-
-	for _, user := range users {
-		if user.ID == id {
-			c.JSON(http.StatusOK, user)
-			return
-		}
-	}
-
-	c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-}
-
-// @Summary Update user
-// @Description Update an existing user by ID
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param id path int true "User ID"
-// @Param user body models.UpdateUserRequest true "Updated user data"
-// @Success 200 {object} models.User
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Router /api/v1/users/{id} [put]
-func UpdateUser(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
-		return
-	}
-
-	var req models.UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	for i, user := range users {
-		if user.ID == id {
-			// Update only provided fields
-			if req.Name != nil {
-				users[i].Name = *req.Name
-			}
-			if req.Email != nil {
-				users[i].Email = *req.Email
-			}
-			if req.Role != nil {
-				users[i].Role = *req.Role
-			}
-			if req.Avatar != nil {
-				users[i].Avatar = *req.Avatar
-			}
-			if req.Bio != nil {
-				users[i].Bio = *req.Bio
-			}
-			if req.Website != nil {
-				users[i].Website = *req.Website
-			}
-			if req.Location != nil {
-				users[i].Location = *req.Location
-			}
-			if req.Skills != nil {
-				users[i].Skills = *req.Skills
-			}
-			users[i].UpdatedAt = time.Now()
-
-			c.JSON(http.StatusOK, users[i])
-			return
-		}
-	}
-
-	c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-}
-
-// @Summary Delete user
-// @Description Delete a user by ID
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param id path int true "User ID"
-// @Success 204 "No Content"
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Router /api/v1/users/{id} [delete]
-func DeleteUser(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
-		return
-	}
-
-	for i, user := range users {
-		if user.ID == id {
-			users = append(users[:i], users[i+1:]...)
-			c.Status(http.StatusNoContent)
-			return
-		}
-	}
-
-	c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-}
\ No newline at end of file
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"portfolio-api/bots"
+	"portfolio-api/models"
+	"portfolio-api/referrer"
+	"portfolio-api/repository"
+	"portfolio-api/stats"
+	"portfolio-api/useragent"
+	"portfolio-api/visitor"
+)
+
+// transparentGIF is a 1x1 transparent GIF served by the tracking pixel
+var transparentGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// RecordVisit records a page visit
+// @Summary Record a visit
+// @Description Record a page visit. Accepts JSON sent as application/json or as
+// @Description text/plain by navigator.sendBeacon, and form-encoded bodies.
+// @Tags stats
+// @Accept json
+// @Accept plain
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param visit body models.VisitRequest true "Visit data"
+// @Success 201 {object} map[string]interface{}
+// @Success 200 {object} map[string]interface{} "Not recorded because of Do Not Track or Global Privacy Control"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /stats/visit [post]
+func (h *Handler) RecordVisit(c *gin.Context) {
+	var req models.VisitRequest
+	if err := bindBeacon(c, &req); err != nil {
+		respondBeaconError(c, err)
+		return
+	}
+
+	visit, err := h.recordVisit(c, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record visit"})
+		return
+	}
+	if visit == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Visit not recorded: Do Not Track or Global Privacy Control is enabled"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Visit recorded successfully",
+		"id":      visit.ID,
+	})
+}
+
+// TrackPixel records a visit from an <img> tag and returns a transparent GIF
+// @Summary Tracking pixel
+// @Description Record a visit from an image request, for pages without JavaScript.
+// @Description Without a page parameter the path of the Referer header is used.
+// @Tags stats
+// @Produce image/gif
+// @Param page query string false "Visited page"
+// @Param referrer query string false "Referrer of the visited page"
+// @Param country query string false "Country fallback when the IP cannot be located"
+// @Param project_id query string false "Project the page shows"
+// @Success 200 {file} binary
+// @Router /stats/pixel.gif [get]
+func (h *Handler) TrackPixel(c *gin.Context) {
+	req := models.VisitRequest{
+		Page:      c.Query("page"),
+		Referrer:  c.Query("referrer"),
+		Country:   c.Query("country"),
+		ProjectID: c.Query("project_id"),
+	}
+	// An image on a static page has no query; the Referer is the page itself
+	if req.Page == "" {
+		req.Page = refererPath(c.Request.Referer())
+	}
+
+	// The image is always served so pages never show a broken one
+	if req.Page != "" && len(req.Page) <= 255 {
+		if _, err := h.recordVisit(c, req); err != nil {
+			log.Printf("Failed to record pixel visit: %v", err)
+		}
+	}
+
+	c.Header("Cache-Control", "no-store, no-cache, must-revalidate, max-age=0")
+	c.Data(http.StatusOK, "image/gif", transparentGIF)
+}
+
+// RecordVisitEvent extends a visit with a heartbeat or leave event
+// @Summary Record a visit event
+// @Description Report that the page of a recorded visit is still open (heartbeat)
+// @Description or was closed (leave), measuring time on page and session duration.
+// @Description Accepts the same body encodings as POST /stats/visit.
+// @Tags stats
+// @Accept json
+// @Accept plain
+// @Accept x-www-form-urlencoded
+// @Param event body models.VisitEventRequest true "Visit event"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /stats/event [post]
+func (h *Handler) RecordVisitEvent(c *gin.Context) {
+	var req models.VisitEventRequest
+	if err := bindBeacon(c, &req); err != nil {
+		respondBeaconError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	visit, err := h.visits.Get(ctx, req.VisitID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Visit not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch visit"})
+		return
+	}
+
+	// Only the visitor who made the visit may extend it; other clients get
+	// the same response as for an unknown visit. The ID is hashed with the
+	// salt of the day the visit started, which may be before midnight.
+	at := time.Now().UTC()
+	visitorID, err := h.visitors.ID(ctx, visit.CreatedAt, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record event"})
+		return
+	}
+	if visit.VisitorID == "" || visitorID != visit.VisitorID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Visit not found"})
+		return
+	}
+
+	// A page left open after the session timed out starts no new activity
+	if at.Sub(stats.LastActivity(*visit)) > stats.SessionTimeout {
+		c.JSON(http.StatusConflict, gin.H{"error": "Session has expired"})
+		return
+	}
+
+	if err := h.visits.SetEndedAt(ctx, visit.ID, at); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record event"})
+		return
+	}
+	if !visit.IsBot {
+		h.live.Touch(visit.VisitorID, at)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// maxBeaconSize bounds the body of a visit beacon; real ones take a few
+// hundred bytes
+const maxBeaconSize = 8 << 10
+
+// bindBeacon decodes a request from JSON whatever its content type, since
+// navigator.sendBeacon sends strings as text/plain, or from a form
+func bindBeacon(c *gin.Context, obj interface{}) error {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBeaconSize)
+	body, err := c.GetRawData()
+	if err != nil {
+		return err
+	}
+
+	isForm := c.ContentType() == binding.MIMEPOSTForm || c.ContentType() == binding.MIMEMultipartPOSTForm
+	if isForm && !bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		return c.ShouldBindWith(obj, binding.Form)
+	}
+	return binding.JSON.BindBody(body, obj)
+}
+
+// respondBeaconError answers 413 for an oversized beacon and 400 otherwise
+func respondBeaconError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Request body must be at most %d bytes", maxBeaconSize)})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// refererPath returns the path of a Referer URL, or "" when there is none
+func refererPath(referer string) string {
+	u, err := url.Parse(referer)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	if u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+// recordVisit classifies, locates, hashes and stores a visit. It returns nil
+// without an error when the client has opted out of tracking.
+func (h *Handler) recordVisit(c *gin.Context, req models.VisitRequest) (*models.Visit, error) {
+	if visitor.OptedOut(c.Request) {
+		return nil, nil
+	}
+
+	// Only the User-Agent header is trusted: bot detection and the visitor
+	// hash depend on it, and RecordVisitEvent hashes the header too
+	userAgent := c.Request.UserAgent()
+	client := useragent.Parse(userAgent)
+
+	// UTM parameters are stored apart so pages group without them
+	page, utm := referrer.ParseUTM(req.Page)
+	source := h.referrers.Classify(req.Referrer, utm)
+
+	ctx := c.Request.Context()
+	projectID, err := h.visitedProject(ctx, req.ProjectID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	// The location comes from the GeoIP database; the client's claimed
+	// country is only used when the address cannot be resolved
+	ip := c.ClientIP()
+	location := h.geo.Locate(ip)
+	if location.Country == "" {
+		location.Country = strings.ToUpper(strings.TrimSpace(req.Country))
+	}
+
+	// The IP address only feeds the daily visitor hash and is not stored
+	at := time.Now().UTC()
+	visitorID, err := h.visitors.ID(ctx, at, ip, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	visit := &models.Visit{
+		Page:           page,
+		UserAgent:      userAgent,
+		Country:        location.Country,
+		City:           location.City,
+		Referrer:       req.Referrer,
+		ReferrerHost:   source.Host,
+		Channel:        source.Channel,
+		UTMSource:      utm.Source,
+		UTMMedium:      utm.Medium,
+		UTMCampaign:    utm.Campaign,
+		ProjectID:      projectID,
+		VisitorID:      visitorID,
+		Browser:        client.Browser,
+		BrowserVersion: client.BrowserVersion,
+		OS:             client.OS,
+		DeviceType:     client.DeviceType,
+		CreatedAt:      at,
+	}
+
+	// Bot visits are kept but flagged so stats can exclude them
+	visit.BotReason = h.bots.Detect(bots.Client{
+		IP:        ip,
+		UserAgent: userAgent,
+		Info:      client,
+		VisitorID: visitorID,
+	}, at)
+	visit.IsBot = visit.BotReason != ""
+
+	if err := h.visits.Create(ctx, visit); err != nil {
+		return nil, err
+	}
+
+	// The live feed shows people, not crawlers
+	if !visit.IsBot {
+		h.live.Publish(*visit)
+	}
+	return visit, nil
+}
+
+// projectPagePattern matches project pages such as /projects/{id} and
+// /projects/{slug}, including hash routes like /#/projects/{slug}
+var projectPagePattern = regexp.MustCompile(`/projects?/([0-9A-Za-z]+(?:-[0-9A-Za-z]+)*)(?:[/?#]|$)`)
+
+// visitedProject returns the ID of the project a visit is about: the one
+// given explicitly, or the one in the page path. Unknown projects are ignored.
+func (h *Handler) visitedProject(ctx context.Context, projectID, page string) (string, error) {
+	if projectID == "" {
+		match := projectPagePattern.FindStringSubmatch(page)
+		if match == nil {
+			return "", nil
+		}
+		projectID = match[1]
+	}
+
+	project, err := h.findProject(ctx, projectID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return project.ID, nil
+}
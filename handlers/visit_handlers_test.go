@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"portfolio-api/bots"
+	"portfolio-api/geoip"
+	"portfolio-api/live"
+	"portfolio-api/models"
+	"portfolio-api/notify"
+	"portfolio-api/referrer"
+	"portfolio-api/repository"
+	"portfolio-api/spam"
+)
+
+// newTestHandler returns a Handler backed by memory repositories
+func newTestHandler(t *testing.T) (*Handler, *repository.Repositories) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	repos := repository.NewMemory()
+	detector, err := bots.NewDetector(bots.Config{MaxPerMinute: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := New(repos, notify.Nop(), spam.NewFilter(50, nil), geoip.Nop(), detector,
+		referrer.NewClassifier(referrer.DefaultRules()), live.NewHub(5*time.Minute))
+	return h, repos
+}
+
+func TestRecordVisitTrustsOnlyTheUserAgentHeader(t *testing.T) {
+	h, repos := newTestHandler(t)
+	router := gin.New()
+	router.POST("/stats/visit", h.RecordVisit)
+
+	// A crawler claiming a browser user agent in the body is still a crawler
+	body := `{"page": "/projects", "user_agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36"}`
+	req := httptest.NewRequest(http.MethodPost, "/stats/visit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Googlebot/2.1 (+http://www.google.com/bot.html)")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", w.Code, w.Body)
+	}
+
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	visit, err := repos.Visits.Get(req.Context(), resp.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !visit.IsBot || visit.BotReason != bots.ReasonUserAgent {
+		t.Errorf("is_bot = %v, bot_reason = %q; want a user agent bot", visit.IsBot, visit.BotReason)
+	}
+	if !strings.HasPrefix(visit.UserAgent, "Googlebot") {
+		t.Errorf("stored user agent %q, want the header", visit.UserAgent)
+	}
+}
+
+func TestRecordVisitEventAfterMidnight(t *testing.T) {
+	h, repos := newTestHandler(t)
+	router := gin.New()
+	router.POST("/stats/event", h.RecordVisitEvent)
+
+	// A visit started ten minutes before midnight, kept alive by events since
+	const ip, userAgent = "203.0.113.7", "Mozilla/5.0 (X11; Linux x86_64) Firefox/120.0"
+	now := time.Now().UTC()
+	startedAt := now.Truncate(24 * time.Hour).Add(-10 * time.Minute)
+	lastEvent := now.Add(-time.Minute)
+	visitorID, err := h.visitors.ID(context.Background(), startedAt, ip, userAgent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	visit := models.Visit{Page: "/", VisitorID: visitorID, CreatedAt: startedAt, EndedAt: &lastEvent}
+	if err := repos.Visits.Create(context.Background(), &visit); err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"visit_id": "` + visit.ID + `", "type": "heartbeat"}`
+	req := httptest.NewRequest(http.MethodPost, "/stats/event", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d: %s", w.Code, http.StatusNoContent, w.Body)
+	}
+}
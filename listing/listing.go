@@ -0,0 +1,237 @@
+// Package listing parses the pagination, sorting and field selection
+// parameters shared by every list endpoint and applies them to a list.
+// Small lists are sorted and paged in memory after the repository has
+// filtered them; lists that grow without bound describe the page they need
+// as a Window so the database sorts and pages them instead.
+package listing
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLimit is the page size when the limit parameter is absent
+	DefaultLimit = 20
+	// MaxLimit bounds the limit parameter
+	MaxLimit = 100
+	// MaxPage bounds the page parameter, keeping offsets within an int32
+	MaxPage = 1 << 24
+)
+
+// timeLayout formats times at a fixed width so cursor values sort like the times
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// SortKey orders a list by one field
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// Resource describes how a list of T is sorted and which fields it has
+type Resource[T any] struct {
+	// Sort maps each sortable field to its value: a string, bool, int,
+	// float64, time.Time or *time.Time. Nil values sort first.
+	Sort map[string]func(T) interface{}
+	// DefaultSort applies when the sort parameter is absent
+	DefaultSort []SortKey
+	// ID breaks ties between equal sort values, keeping pages stable
+	ID func(T) string
+}
+
+// Query is a parsed list request. A positive Page selects offset
+// pagination; otherwise pages follow cursors, starting at the first page.
+type Query struct {
+	Limit  int
+	Page   int
+	Cursor *Cursor
+	Sort   []SortKey
+	Fields []string
+}
+
+// Parse reads the limit, page, cursor, sort and fields parameters
+func Parse[T any](values url.Values, resource Resource[T]) (Query, error) {
+	query := Query{Limit: DefaultLimit, Sort: resource.DefaultSort}
+
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > MaxLimit {
+			return Query{}, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+		}
+		query.Limit = n
+	}
+
+	if sortParam := values.Get("sort"); sortParam != "" {
+		keys, err := parseSort(sortParam, resource)
+		if err != nil {
+			return Query{}, err
+		}
+		query.Sort = keys
+	}
+
+	page, cursor := values.Get("page"), values.Get("cursor")
+	if page != "" && cursor != "" {
+		return Query{}, errors.New("use either page or cursor, not both")
+	}
+	if page != "" {
+		n, err := strconv.Atoi(page)
+		if err != nil || n < 1 || n > MaxPage {
+			return Query{}, fmt.Errorf("page must be between 1 and %d", MaxPage)
+		}
+		query.Page = n
+	}
+	if cursor != "" {
+		c, err := decodeCursor(cursor)
+		if err != nil || c.Sort != formatSort(query.Sort) || len(c.Values) != len(query.Sort) {
+			return Query{}, ErrInvalidCursor
+		}
+		query.Cursor = c
+	}
+
+	if fields := values.Get("fields"); fields != "" {
+		known := jsonFields(reflect.TypeOf((*T)(nil)).Elem())
+		for _, field := range strings.Split(fields, ",") {
+			field = strings.TrimSpace(field)
+			if !known[field] {
+				return Query{}, fmt.Errorf("unknown field %q", field)
+			}
+			query.Fields = append(query.Fields, field)
+		}
+	}
+
+	return query, nil
+}
+
+// parseSort reads a sort parameter such as "title,-created_at"
+func parseSort[T any](value string, resource Resource[T]) ([]SortKey, error) {
+	keys := []SortKey{}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		key := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := resource.Sort[key.Field]; !ok {
+			return nil, fmt.Errorf("cannot sort by %q; use %s", key.Field, strings.Join(sortFields(resource), ", "))
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("duplicate sort field %q", key.Field)
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// sortFields lists the sortable fields of a resource alphabetically
+func sortFields[T any](resource Resource[T]) []string {
+	fields := make([]string, 0, len(resource.Sort))
+	for field := range resource.Sort {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// formatSort renders sort keys the way the sort parameter spells them
+func formatSort(keys []SortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key.Field
+		if key.Desc {
+			parts[i] = "-" + key.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// jsonFields returns the JSON names of a struct type's fields
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = true
+	}
+	return fields
+}
+
+// normalize converts a sort value into the form cursors store: nil, a
+// string, a bool or a float64, with times as fixed-width UTC strings
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(timeLayout)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.UTC().Format(timeLayout)
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		return v
+	}
+}
+
+// compareValues orders two normalized values of the same field. Values of
+// different types, which only a forged cursor can produce, order by type.
+func compareValues(a, b interface{}) int {
+	if ra, rb := typeRank(a), typeRank(b); ra != rb {
+		return ra - rb
+	}
+
+	switch x := a.(type) {
+	case bool:
+		y := b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		}
+		return 1
+	case float64:
+		y := b.(float64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(x, b.(string))
+	}
+	return 0
+}
+
+// typeRank orders the types of normalized values, nil first
+func typeRank(value interface{}) int {
+	switch value.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64:
+		return 2
+	case string:
+		return 3
+	}
+	return 4
+}
@@ -0,0 +1,288 @@
+package listing
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type item struct {
+	ID      string     `json:"id"`
+	Name    string     `json:"name"`
+	Rank    int        `json:"rank"`
+	Created *time.Time `json:"created,omitempty"`
+}
+
+var items = Resource[item]{
+	Sort: map[string]func(item) interface{}{
+		"name":    func(i item) interface{} { return strings.ToLower(i.Name) },
+		"rank":    func(i item) interface{} { return i.Rank },
+		"created": func(i item) interface{} { return i.Created },
+	},
+	DefaultSort: []SortKey{{Field: "rank", Desc: true}},
+	ID:          func(i item) string { return i.ID },
+}
+
+// sample returns items with repeated ranks and names and some missing times
+func sample() []item {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	list := []item{}
+	for i := 0; i < 23; i++ {
+		it := item{ID: "id" + strconv.Itoa(10+i), Name: string(rune('a' + i%5)), Rank: i % 4}
+		if i%3 != 0 {
+			created := base.Add(time.Duration(i%7) * time.Hour)
+			it.Created = &created
+		}
+		list = append(list, it)
+	}
+	return list
+}
+
+func ids(list []item) []string {
+	out := []string{}
+	for _, it := range list {
+		out = append(out, it.ID)
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantSort []SortKey
+		wantErr  string
+	}{
+		{name: "default sort", query: "", wantSort: items.DefaultSort},
+		{name: "several keys", query: "sort=name,-created", wantSort: []SortKey{{Field: "name"}, {Field: "created", Desc: true}}},
+		{name: "unknown field", query: "sort=title", wantErr: `cannot sort by "title"; use created, name, rank`},
+		{name: "duplicate field", query: "sort=name,-name", wantErr: `duplicate sort field "name"`},
+		{name: "limit too large", query: "limit=101", wantErr: "limit must be between 1 and 100"},
+		{name: "page and cursor", query: "page=1&cursor=abc", wantErr: "use either page or cursor, not both"},
+		{name: "page too large", query: "page=99999999999", wantErr: "page must be between 1 and 16777216"},
+		{name: "garbage cursor", query: "cursor=abc", wantErr: ErrInvalidCursor.Error()},
+		{name: "unknown field selection", query: "fields=id,secret", wantErr: `unknown field "secret"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			query, err := Parse(values, items)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(query.Sort, tt.wantSort) {
+				t.Errorf("sort = %v, want %v", query.Sort, tt.wantSort)
+			}
+		})
+	}
+}
+
+func TestParseRejectsCursorOfAnotherSort(t *testing.T) {
+	values := url.Values{"limit": {"5"}, "sort": {"name"}}
+	query, _ := Parse(values, items)
+	page := Paginate(sample(), query, items)
+	cursor := page.next.Get("cursor")
+
+	if _, err := Parse(url.Values{"sort": {"-name"}, "cursor": {cursor}}, items); err != ErrInvalidCursor {
+		t.Errorf("got %v, want ErrInvalidCursor", err)
+	}
+	if _, err := Parse(url.Values{"sort": {"name"}, "cursor": {cursor}}, items); err != nil {
+		t.Errorf("same sort: %v", err)
+	}
+}
+
+// walk follows next links from the first page, then prev links back, and
+// returns the IDs seen each way
+func walk(t *testing.T, list []item, sortParam string, limit int) (forward, backward []string) {
+	t.Helper()
+	values := url.Values{"sort": {sortParam}, "limit": {strconv.Itoa(limit)}}
+	var page Page[item]
+	for i := 0; ; i++ {
+		if i > len(list) {
+			t.Fatal("next links do not end")
+		}
+		query, err := Parse(values, items)
+		if err != nil {
+			t.Fatal(err)
+		}
+		page = Paginate(list, query, items)
+		forward = append(forward, ids(page.Items)...)
+		if page.next == nil {
+			break
+		}
+		values.Set("cursor", page.next.Get("cursor"))
+	}
+
+	backward = ids(page.Items)
+	for i := 0; page.prev != nil; i++ {
+		if i > len(list) {
+			t.Fatal("prev links do not end")
+		}
+		values.Set("cursor", page.prev.Get("cursor"))
+		query, err := Parse(values, items)
+		if err != nil {
+			t.Fatal(err)
+		}
+		page = Paginate(list, query, items)
+		backward = append(ids(page.Items), backward...)
+	}
+	return forward, backward
+}
+
+func TestPaginateCursors(t *testing.T) {
+	list := sample()
+	for _, sortParam := range []string{"rank", "-rank", "name,-rank", "created", "-created,name"} {
+		t.Run(sortParam, func(t *testing.T) {
+			query, _ := Parse(url.Values{"sort": {sortParam}, "limit": {"100"}}, items)
+			want := ids(Paginate(list, query, items).Items)
+			if len(want) != len(list) {
+				t.Fatalf("one page holds %d items, want %d", len(want), len(list))
+			}
+
+			forward, backward := walk(t, list, sortParam, 4)
+			if !reflect.DeepEqual(forward, want) {
+				t.Errorf("next links visit %v, want %v", forward, want)
+			}
+			if !reflect.DeepEqual(backward, want) {
+				t.Errorf("prev links visit %v, want %v", backward, want)
+			}
+		})
+	}
+}
+
+func TestPaginateOrder(t *testing.T) {
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+	list := []item{
+		{ID: "b", Name: "Beta", Rank: 1, Created: &late},
+		{ID: "a", Name: "alpha", Rank: 1},
+		{ID: "c", Name: "beta", Rank: 2, Created: &early},
+	}
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		// Names compare without case, then IDs break the tie
+		{sort: "name", want: []string{"a", "b", "c"}},
+		{sort: "-rank", want: []string{"c", "a", "b"}},
+		// Missing times sort first
+		{sort: "created", want: []string{"a", "c", "b"}},
+		{sort: "-created", want: []string{"b", "c", "a"}},
+	}
+
+	for _, tt := range tests {
+		query, _ := Parse(url.Values{"sort": {tt.sort}}, items)
+		if got := ids(Paginate(list, query, items).Items); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sort %s: got %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+}
+
+func TestPaginatePages(t *testing.T) {
+	list := sample()
+	tests := []struct {
+		page      int
+		wantCount int
+		wantNext  string
+		wantPrev  string
+	}{
+		{page: 1, wantCount: 10, wantNext: "2"},
+		{page: 3, wantCount: 3, wantPrev: "2"},
+		// Past the end, prev points at the last page
+		{page: 9, wantCount: 0, wantPrev: "3"},
+	}
+
+	for _, tt := range tests {
+		query, _ := Parse(url.Values{"limit": {"10"}, "page": {strconv.Itoa(tt.page)}}, items)
+		page := Paginate(list, query, items)
+		if len(page.Items) != tt.wantCount || page.Total != len(list) {
+			t.Errorf("page %d: %d of %d items, want %d of %d", tt.page, len(page.Items), page.Total, tt.wantCount, len(list))
+		}
+		if got := page.next.Get("page"); got != tt.wantNext {
+			t.Errorf("page %d: next = %q, want %q", tt.page, got, tt.wantNext)
+		}
+		if got := page.prev.Get("page"); got != tt.wantPrev {
+			t.Errorf("page %d: prev = %q, want %q", tt.page, got, tt.wantPrev)
+		}
+	}
+}
+
+// fetch plays storage that pages a list itself: it applies a window to an
+// already sorted list the way a database query would
+func fetch(sorted []item, sortKeys []SortKey, window Window) (rows []item, pastCursor int) {
+	order := append([]item{}, sorted...)
+	if window.IDDesc {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+	if window.Cursor != nil {
+		kept := []item{}
+		for _, it := range order {
+			c := compareKeys(sortKeys, sortValues(it, sortKeys, items), it.ID, window.Cursor.Values, window.Cursor.ID)
+			if (c > 0 && !window.IDDesc) || (c < 0 && window.IDDesc) {
+				kept = append(kept, it)
+			}
+		}
+		order = kept
+	}
+	pastCursor = len(order)
+	start := min(len(order), window.Offset)
+	return order[start:min(len(order), start+window.Limit)], pastCursor
+}
+
+func TestAssembleMatchesPaginate(t *testing.T) {
+	list := sample()
+	for _, sortParam := range []string{"rank", "-created,name"} {
+		values := url.Values{"sort": {sortParam}, "limit": {"100"}}
+		query, _ := Parse(values, items)
+		sorted := Paginate(list, query, items).Items
+
+		for _, position := range []url.Values{{"page": {"2"}}, {"page": {"7"}}, {}} {
+			values := url.Values{"sort": {sortParam}, "limit": {"4"}}
+			for key, v := range position {
+				values[key] = v
+			}
+
+			// Follow a few links in both directions, comparing each page
+			for step := 0; step < 4; step++ {
+				query, err := Parse(values, items)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := Paginate(list, query, items)
+				rows, pastCursor := fetch(sorted, query.Sort, query.Window())
+				got := Assemble(rows, len(list), pastCursor, query, items)
+
+				if !reflect.DeepEqual(ids(got.Items), ids(want.Items)) || !reflect.DeepEqual(got.next, want.next) || !reflect.DeepEqual(got.prev, want.prev) {
+					t.Fatalf("sort %s, %v: Assemble gave %v next %v prev %v, want %v next %v prev %v",
+						sortParam, values, ids(got.Items), got.next, got.prev, ids(want.Items), want.next, want.prev)
+				}
+
+				link := want.next
+				if step%2 == 1 || link == nil {
+					link = want.prev
+				}
+				if link == nil {
+					break
+				}
+				values.Del("page")
+				values.Del("cursor")
+				for key, v := range link {
+					values[key] = v
+				}
+			}
+		}
+	}
+}
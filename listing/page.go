@@ -0,0 +1,227 @@
+package listing
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Cursor marks a position in a sorted list: the sort values and ID of an
+// item, and whether the page lies after or before it
+type Cursor struct {
+	Before bool          `json:"b,omitempty"`
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+	ID     string        `json:"id"`
+}
+
+// encode renders the cursor as an opaque URL-safe string
+func (c Cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Page is one page of a sorted list
+type Page[T any] struct {
+	Items []T
+	Total int
+	query Query
+	next  url.Values
+	prev  url.Values
+}
+
+// Envelope is the body of a list response. Next and Prev are links to the
+// neighbouring pages, or null at either end.
+type Envelope struct {
+	Data       interface{} `json:"data"`
+	Count      int         `json:"count" example:"20"`
+	Total      int         `json:"total" example:"57"`
+	Limit      int         `json:"limit" example:"20"`
+	Page       int         `json:"page,omitempty" example:"2"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
+	Next       *string     `json:"next" example:"/api/v1/projects?limit=20&page=3"`
+	Prev       *string     `json:"prev" example:"/api/v1/projects?limit=20&page=1"`
+}
+
+// Paginate sorts items and cuts out the page the query asks for
+func Paginate[T any](items []T, query Query, resource Resource[T]) Page[T] {
+	type keyed struct {
+		item   T
+		values []interface{}
+		id     string
+	}
+	sorted := make([]keyed, len(items))
+	for i, item := range items {
+		sorted[i] = keyed{item: item, values: sortValues(item, query.Sort, resource), id: resource.ID(item)}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareKeys(query.Sort, sorted[i].values, sorted[i].id, sorted[j].values, sorted[j].id) < 0
+	})
+
+	total := len(sorted)
+	start, end := 0, 0
+	switch {
+	case query.Page > 0:
+		start = min(total, (query.Page-1)*query.Limit)
+		end = min(total, start+query.Limit)
+	case query.Cursor != nil:
+		// The first item past the cursor position
+		i := sort.Search(total, func(i int) bool {
+			c := compareKeys(query.Sort, sorted[i].values, sorted[i].id, query.Cursor.Values, query.Cursor.ID)
+			return c > 0 || (query.Cursor.Before && c == 0)
+		})
+		if query.Cursor.Before {
+			start, end = max(0, i-query.Limit), i
+		} else {
+			start, end = i, min(total, i+query.Limit)
+		}
+	default:
+		end = min(total, query.Limit)
+	}
+
+	pageItems := make([]T, 0, end-start)
+	for _, k := range sorted[start:end] {
+		pageItems = append(pageItems, k.item)
+	}
+	return newPage(pageItems, start, total, query, resource)
+}
+
+// newPage links a page holding the items from position start of a sorted
+// list of total items to its neighbours
+func newPage[T any](items []T, start, total int, query Query, resource Resource[T]) Page[T] {
+	page := Page[T]{Items: items, Total: total, query: query}
+	end := start + len(items)
+
+	if query.Page > 0 {
+		if end < total {
+			page.next = url.Values{"page": {strconv.Itoa(query.Page + 1)}}
+		}
+		if query.Page > 1 {
+			// Past the end, the previous page is the last one
+			lastPage := max(1, (total+query.Limit-1)/query.Limit)
+			page.prev = url.Values{"page": {strconv.Itoa(min(query.Page-1, lastPage))}}
+		}
+		return page
+	}
+	if len(items) > 0 {
+		sortParam := formatSort(query.Sort)
+		if end < total {
+			last := items[len(items)-1]
+			cursor := Cursor{Sort: sortParam, Values: sortValues(last, query.Sort, resource), ID: resource.ID(last)}
+			page.next = url.Values{"cursor": {cursor.encode()}}
+		}
+		if start > 0 {
+			first := items[0]
+			cursor := Cursor{Before: true, Sort: sortParam, Values: sortValues(first, query.Sort, resource), ID: resource.ID(first)}
+			page.prev = url.Values{"cursor": {cursor.encode()}}
+		}
+	}
+	return page
+}
+
+// sortValues returns the normalized values an item sorts by
+func sortValues[T any](item T, keys []SortKey, resource Resource[T]) []interface{} {
+	values := make([]interface{}, len(keys))
+	for j, key := range keys {
+		values[j] = normalize(resource.Sort[key.Field](item))
+	}
+	return values
+}
+
+// compareKeys orders two items by their sort values, then by ID
+func compareKeys(keys []SortKey, values []interface{}, id string, otherValues []interface{}, otherID string) int {
+	for j, key := range keys {
+		if c := compareValues(values[j], otherValues[j]); c != 0 {
+			if key.Desc {
+				return -c
+			}
+			return c
+		}
+	}
+	return strings.Compare(id, otherID)
+}
+
+// Envelope renders the page, keeping only the selected fields of each item.
+// Links repeat the request URL with the page or cursor replaced.
+func (p Page[T]) Envelope(request *url.URL) (Envelope, error) {
+	envelope := Envelope{
+		Data:  p.Items,
+		Count: len(p.Items),
+		Total: p.Total,
+		Limit: p.query.Limit,
+		Page:  p.query.Page,
+		Next:  link(request, p.next),
+		Prev:  link(request, p.prev),
+	}
+	if p.query.Page == 0 {
+		envelope.NextCursor = p.next.Get("cursor")
+		envelope.PrevCursor = p.prev.Get("cursor")
+	}
+
+	if len(p.query.Fields) > 0 {
+		data, err := selectFields(p.Items, p.query.Fields)
+		if err != nil {
+			return Envelope{}, err
+		}
+		envelope.Data = data
+	}
+	return envelope, nil
+}
+
+// link returns the request URL with the page and cursor parameters replaced
+// by position, or nil without a position
+func link(request *url.URL, position url.Values) *string {
+	if position == nil {
+		return nil
+	}
+	query := request.Query()
+	query.Del("page")
+	query.Del("cursor")
+	for key, values := range position {
+		query[key] = values
+	}
+	href := request.Path + "?" + query.Encode()
+	return &href
+}
+
+// selectFields reduces each item to the given JSON fields
+func selectFields[T any](items []T, fields []string) ([]map[string]json.RawMessage, error) {
+	selected := make([]map[string]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(data, &all); err != nil {
+			return nil, err
+		}
+
+		picked := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			// Empty omitempty fields stay absent
+			if value, ok := all[field]; ok {
+				picked[field] = value
+			}
+		}
+		selected = append(selected, picked)
+	}
+	return selected, nil
+}
@@ -0,0 +1,66 @@
+package listing
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidCursor is returned by storage that cannot compare a cursor's
+// values with the fields it sorts by, which only a forged cursor causes
+var ErrInvalidCursor = errors.New("invalid cursor; it must come from a next or prev link with the same sort")
+
+// Window is the part of a sorted list a query selects, for storage that
+// sorts and pages lists itself. Storage orders items by Sort, breaking ties
+// by ID in descending order when IDDesc is set, keeps only the items past
+// Cursor when it is set, then skips Offset items and returns up to Limit.
+type Window struct {
+	Sort   []SortKey
+	IDDesc bool
+	Cursor *Cursor
+	Offset int
+	Limit  int
+}
+
+// Window returns the window storage fetches for the query. Pages before a
+// cursor are fetched in reverse order, walking back from the cursor.
+func (q Query) Window() Window {
+	window := Window{Sort: q.Sort, Limit: q.Limit}
+	switch {
+	case q.Page > 0:
+		window.Offset = (q.Page - 1) * q.Limit
+	case q.Cursor != nil:
+		window.Cursor = q.Cursor
+		if q.Cursor.Before {
+			window.Sort = make([]SortKey, len(q.Sort))
+			for i, key := range q.Sort {
+				window.Sort[i] = SortKey{Field: key.Field, Desc: !key.Desc}
+			}
+			window.IDDesc = true
+		}
+	}
+	return window
+}
+
+// Assemble builds the page of a query from the items storage returned for
+// its window, given the total number of items in the list and, for cursor
+// queries, how many of them lie past the cursor
+func Assemble[T any](items []T, total, pastCursor int, query Query, resource Resource[T]) Page[T] {
+	start := 0
+	switch {
+	case query.Page > 0:
+		start = min(total, (query.Page-1)*query.Limit)
+	case query.Cursor != nil && query.Cursor.Before:
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+		start = max(0, pastCursor-len(items))
+	case query.Cursor != nil:
+		start = max(0, total-pastCursor)
+	}
+	return newPage(items, start, total, query, resource)
+}
+
+// CursorTime parses a time a cursor carries as a sort value
+func CursorTime(value string) (time.Time, error) {
+	return time.Parse(timeLayout, value)
+}
@@ -0,0 +1,156 @@
+// Package live fans accepted visits out to real-time subscribers and keeps a
+// rolling count of active visitors
+package live
+
+import (
+	"sync"
+	"time"
+
+	"portfolio-api/models"
+)
+
+// bufferSize is how many visits a subscriber may fall behind before it is
+// disconnected
+const bufferSize = 64
+
+// Hub is an in-process publish/subscribe hub for visits. Publishing never
+// blocks: a subscriber that cannot keep up is disconnected instead of
+// slowing down visit recording.
+type Hub struct {
+	window time.Duration
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	lastSeen    map[string]time.Time
+	lastPrune   time.Time
+	closed      bool
+}
+
+// Subscription receives the visits published after it was created
+type Subscription struct {
+	hub    *Hub
+	events chan models.LiveVisit
+}
+
+// NewHub creates a Hub counting visitors active within window as active
+func NewHub(window time.Duration) *Hub {
+	return &Hub{
+		window:      window,
+		subscribers: make(map[*Subscription]struct{}),
+		lastSeen:    make(map[string]time.Time),
+	}
+}
+
+// Publish records the visitor's activity and delivers the visit to every
+// subscriber
+func (h *Hub) Publish(visit models.Visit) {
+	event := models.LiveVisit{
+		Page:         visit.Page,
+		Country:      visit.Country,
+		City:         visit.City,
+		DeviceType:   visit.DeviceType,
+		Browser:      visit.Browser,
+		ReferrerHost: visit.ReferrerHost,
+		Channel:      visit.Channel,
+		At:           visit.CreatedAt,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.touch(visit.VisitorID, visit.CreatedAt)
+	for sub := range h.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// Touch records activity of a visitor without publishing a visit, e.g. a
+// heartbeat from an open page
+func (h *Hub) Touch(visitorID string, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.touch(visitorID, at)
+}
+
+// ActiveVisitors counts the visitors active within the window before now
+func (h *Hub) ActiveVisitors(now time.Time) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.prune(now)
+	return len(h.lastSeen)
+}
+
+// Subscribe starts a subscription; it reports false once the hub is closed
+func (h *Hub) Subscribe() (*Subscription, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, false
+	}
+	sub := &Subscription{hub: h, events: make(chan models.LiveVisit, bufferSize)}
+	h.subscribers[sub] = struct{}{}
+	return sub, true
+}
+
+// Close ends every subscription and rejects new ones, letting streams
+// finish before the server shuts down
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		h.remove(sub)
+	}
+}
+
+// Events delivers the published visits. It is closed when the subscription
+// ends, including when the subscriber fell too far behind.
+func (s *Subscription) Events() <-chan models.LiveVisit {
+	return s.events
+}
+
+// Close ends the subscription; it is safe to call more than once
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// remove ends a subscription; the caller holds h.mu
+func (h *Hub) remove(sub *Subscription) {
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// touch records activity; the caller holds h.mu
+func (h *Hub) touch(visitorID string, at time.Time) {
+	if visitorID == "" {
+		return
+	}
+	if at.After(h.lastSeen[visitorID]) {
+		h.lastSeen[visitorID] = at
+	}
+	if at.Sub(h.lastPrune) > h.window {
+		h.prune(at)
+	}
+}
+
+// prune forgets visitors inactive for longer than the window; the caller holds h.mu
+func (h *Hub) prune(now time.Time) {
+	cutoff := now.Add(-h.window)
+	for visitorID, at := range h.lastSeen {
+		if at.Before(cutoff) {
+			delete(h.lastSeen, visitorID)
+		}
+	}
+	h.lastPrune = now
+}
@@ -1,17 +1,34 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
+	_ "time/tzdata" // stats bucketing by IANA time zone works in minimal containers
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"portfolio-api/auth"
+	"portfolio-api/bots"
+	"portfolio-api/config"
 	"portfolio-api/database"
+	"portfolio-api/geoip"
 	"portfolio-api/handlers"
+	"portfolio-api/live"
+	"portfolio-api/models"
+	"portfolio-api/notify"
+	"portfolio-api/publish"
+	"portfolio-api/referrer"
+	"portfolio-api/repository"
+	"portfolio-api/rollup"
+	"portfolio-api/spam"
 )
 
 // @title Portfolio API
@@ -30,17 +47,119 @@ import (
 // @BasePath /api/v1
 // @schemes http https
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key (pk_...) or "Bearer <jwt>" in the Authorization header
+
 func main() {
-	// Initialize Supabase database
-	if err := database.InitSupabase(); err != nil {
-		log.Fatalf("Failed to initialize Supabase: %v", err)
+	cfg := config.Load()
+
+	// Management subcommands: `portfolio-api migrate ...` and `portfolio-api apikey ...`
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			if err := runMigrate(cfg, os.Args[2:]); err != nil {
+				log.Fatalf("Migration failed: %v", err)
+			}
+			return
+		case "apikey":
+			if err := runAPIKey(cfg, os.Args[2:]); err != nil {
+				log.Fatalf("API key command failed: %v", err)
+			}
+			return
+		}
+	}
+
+	// Initialize the storage backend selected by STORAGE_DRIVER
+	repos, err := openRepositories(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize %s storage: %v", cfg.StorageDriver, err)
 	}
-	defer database.CloseSupabase()
+	defer database.Close()
 
 	// Insert sample data on startup
-	if err := database.InsertSampleData(); err != nil {
+	if err := repository.SeedSampleData(context.Background(), repos); err != nil {
 		log.Printf("Warning: Failed to insert sample data: %v", err)
 	}
+	if err := repository.AssignProjectSlugs(context.Background(), repos); err != nil {
+		log.Printf("Warning: Failed to assign project slugs: %v", err)
+	}
+
+	// Contact form emails are delivered in the background
+	notifier, err := newNotifier(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure email notifications: %v", err)
+	}
+
+	// Contact form submissions pass through the spam filter
+	spamFilter, err := newSpamFilter(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure spam protection: %v", err)
+	}
+
+	// Visitors are located from a local GeoIP database when one is configured
+	locator, err := newLocator(cfg)
+	if err != nil {
+		log.Fatalf("Failed to open GeoIP database: %v", err)
+	}
+	defer locator.Close()
+
+	// Crawler, monitor and office visits are flagged as bots
+	botDetector, err := bots.NewDetector(bots.Config{
+		ExcludedNetworks: cfg.BotExcludedNetworks,
+		MaxPerMinute:     cfg.BotMaxVisitsPerMin,
+	})
+	if err != nil {
+		log.Fatalf("Invalid BOT_EXCLUDED_NETWORKS: %v", err)
+	}
+
+	// Referrers are classified into channels; our own site counts as direct
+	referrerRules, err := referrer.LoadRules(cfg.ReferrerRulesFile)
+	if err != nil {
+		log.Fatalf("Failed to load referrer rules: %v", err)
+	}
+	referrers := referrer.NewClassifier(referrerRules, cfg.SiteURL)
+
+	// Recorded visits are pushed to the live feed
+	liveHub := live.NewHub(5 * time.Minute)
+
+	// Wire repositories into the handlers
+	h := handlers.New(repos, notifier, spamFilter, locator, botDetector, referrers, liveHub)
+
+	// Visits are rolled up into hourly and daily aggregates in the background
+	if cfg.RollupIntervalMinutes < 1 {
+		log.Fatal("ANALYTICS_ROLLUP_INTERVAL_MINUTES must be at least 1")
+	}
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	rollups := rollup.NewJob(repos.Visits, repos.Rollups, referrers, cfg.RetentionDays)
+	go rollups.Run(jobCtx, time.Duration(cfg.RollupIntervalMinutes)*time.Minute)
+
+	// Scheduled projects are published once their publish time passes
+	if cfg.PublishIntervalSeconds < 1 {
+		log.Fatal("PUBLISH_INTERVAL_SECONDS must be at least 1")
+	}
+	scheduler := publish.NewScheduler(repos.Projects)
+	go scheduler.Run(jobCtx, time.Duration(cfg.PublishIntervalSeconds)*time.Second)
+
+	// Mutating endpoints require an API key or a bearer JWT
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+		Secret:   cfg.JWTSecret,
+		JWKSFile: cfg.JWKSFile,
+		Issuer:   cfg.JWTIssuer,
+		Audience: cfg.JWTAudience,
+	})
+	if err != nil {
+		log.Fatalf("Failed to configure JWT verification: %v", err)
+	}
+	authenticator := auth.NewAuthenticator(repos.APIKeys, repos.Users, verifier)
+	requireAuth := authenticator.Required()
+	// Public reads also show signed in owners their private profile and
+	// unpublished projects
+	optionalAuth := authenticator.Optional()
+	adminOnly := auth.RequireRole(models.RoleAdmin)
+	canPublish := auth.RequireRole(models.RoleAdmin, models.RoleOwner)
 
 	// Set Gin mode based on environment
 	if os.Getenv("GIN_MODE") == "" {
@@ -48,17 +167,22 @@ func main() {
 	}
 
 	router := gin.Default()
+	if len(cfg.TrustedProxies) > 0 {
+		if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+			log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+		}
+	}
 
 	// CORS configuration for portfolio frontend
-	config := cors.DefaultConfig()
-	config.AllowOrigins = []string{
-		"http://localhost:3000",           // Local Flutter dev
-		"https://fada2020.github.io",   // GitHub Pages
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowOrigins = []string{
+		"http://localhost:3000",      // Local Flutter dev
+		"https://fada2020.github.io", // GitHub Pages
 	}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", "X-Requested-With"}
-	config.AllowCredentials = true
-	router.Use(cors.New(config))
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", "X-Requested-With", "X-API-Key"}
+	corsConfig.AllowCredentials = true
+	router.Use(cors.New(corsConfig))
 
 	// Health check endpoint
 	router.GET("/health", healthCheck)
@@ -69,57 +193,197 @@ func main() {
 		// User management
 		users := v1.Group("/users")
 		{
-			users.GET("", handlers.GetUsers)
-			users.POST("", handlers.CreateUser)
-			users.GET("/:id", handlers.GetUserByID)
-			users.PUT("/:id", handlers.UpdateUser)
-			users.DELETE("/:id", handlers.DeleteUser)
+			users.GET("", optionalAuth, h.GetUsers)
+			users.POST("", requireAuth, adminOnly, h.CreateUser)
+			users.GET("/:id", optionalAuth, h.GetUserByID)
+			users.GET("/:id/projects", optionalAuth, h.GetUserProjects)
+			users.GET("/:id/skills", optionalAuth, h.GetUserSkills)
+			users.PUT("/:id", requireAuth, h.UpdateUser)
+			users.DELETE("/:id", requireAuth, adminOnly, h.DeleteUser)
 		}
 
 		// Projects showcase
 		projects := v1.Group("/projects")
 		{
-			projects.GET("", handlers.GetProjects)
-			projects.GET("/:id", handlers.GetProject)
-			projects.POST("", handlers.CreateProject)
-			projects.PUT("/:id", handlers.UpdateProject)
-			projects.DELETE("/:id", handlers.DeleteProject)
+			projects.GET("", optionalAuth, h.GetProjects)
+			projects.GET("/:id", optionalAuth, h.GetProject)
+			projects.POST("", requireAuth, canPublish, h.CreateProject)
+			projects.PUT("/:id", requireAuth, canPublish, h.UpdateProject)
+			projects.DELETE("/:id", requireAuth, canPublish, h.DeleteProject)
 		}
 
 		// Skills and technologies
 		skills := v1.Group("/skills")
 		{
-			skills.GET("", handlers.GetSkills)
-			skills.POST("", handlers.AddSkill)
-			skills.DELETE("/:id", handlers.RemoveSkill)
+			skills.GET("", h.GetSkills)
+			skills.POST("", requireAuth, canPublish, h.AddSkill)
+			skills.DELETE("/:id", requireAuth, canPublish, h.RemoveSkill)
 		}
 
+		// Full-text search across projects, skills and profiles
+		v1.GET("/search", optionalAuth, h.Search)
+
 		// Contact form
 		contact := v1.Group("/contact")
 		{
-			contact.POST("", handlers.SubmitContactForm)
+			contact.GET("/token", h.GetContactToken)
+			contact.POST("", h.SubmitContactForm)
+
+			// Inbox for the contact form submissions
+			messages := contact.Group("/messages", requireAuth, adminOnly)
+			{
+				messages.GET("", h.ListContactMessages)
+				messages.PATCH("", h.BulkUpdateContactStatus)
+				messages.GET("/:id", h.GetContactMessage)
+				messages.PATCH("/:id", h.UpdateContactMessageStatus)
+				messages.DELETE("/:id", h.DeleteContactMessage)
+			}
 		}
 
 		// Portfolio statistics
 		stats := v1.Group("/stats")
 		{
-			stats.GET("/views", handlers.GetViewStats)
-			stats.GET("/projects", handlers.GetProjectStats)
-			stats.POST("/visit", handlers.RecordVisit)
+			stats.GET("/views", h.GetViewStats)
+			stats.GET("/referrers", h.GetReferrerStats)
+			stats.GET("/campaigns", h.GetCampaignStats)
+			stats.GET("/live", requireAuth, h.StreamLiveVisits)
+			stats.GET("/projects", h.GetProjectStats)
+			stats.POST("/visit", h.RecordVisit)
+			stats.POST("/event", h.RecordVisitEvent)
+			stats.GET("/pixel.gif", h.TrackPixel)
 		}
 	}
 
+	// Outbound project links count clicks before redirecting
+	router.GET("/p/:id/:target", optionalAuth, h.FollowProjectLink)
+
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Start server and shut down gracefully on SIGINT/SIGTERM
+	server := &http.Server{Addr: ":" + cfg.Port, Handler: router}
+	go func() {
+		log.Printf("Starting server on port %s", cfg.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("Shutting down")
+	stopJobs()
+	// Live streams never end on their own; close them so Shutdown can finish
+	liveHub.Close()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Warning: server shutdown: %v", err)
+	}
+	if err := notifier.Close(shutdownCtx); err != nil {
+		log.Printf("Warning: pending email was not delivered: %v", err)
+	}
+}
+
+// newSpamFilter assembles the contact form spam checks from the configuration
+func newSpamFilter(cfg config.Config) (*spam.Filter, error) {
+	if cfg.SpamTokenSecret == "" {
+		log.Println("Warning: SPAM_TOKEN_SECRET is not set; form tokens use a random per-process " +
+			"secret and will be rejected after a restart or by other replicas")
+	}
+	tokens, err := spam.NewTokenIssuer(cfg.SpamTokenSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	keywords := cfg.SpamKeywords
+	if len(keywords) == 0 {
+		keywords = spam.DefaultKeywords
+	}
+
+	// The CAPTCHA goes first so that failed ones do not count against the
+	// rate limits, and anyone naming someone else's email cannot lock them out
+	checks := []spam.Check{}
+	if cfg.CaptchaProvider != "" {
+		verifier, err := spam.NewCaptchaVerifier(cfg.CaptchaProvider, cfg.CaptchaSecret)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, spam.CaptchaCheck{Verifier: verifier})
+	}
+	checks = append(checks,
+		spam.RateLimitCheck{
+			PerIP:    spam.NewRateLimiter(cfg.ContactRateLimitIP, time.Hour),
+			PerEmail: spam.NewRateLimiter(cfg.ContactRateLimitEmail, time.Hour),
+		},
+		spam.HoneypotCheck{},
+		spam.TimingCheck{Tokens: tokens, MinDelay: time.Duration(cfg.SpamMinSubmitSeconds) * time.Second},
+		spam.LinkCheck{MaxLinks: cfg.SpamMaxLinks},
+		spam.KeywordCheck{Keywords: keywords},
+	)
+
+	return spam.NewFilter(cfg.SpamThreshold, tokens, checks...), nil
+}
+
+// newLocator opens the GeoIP database, or returns a no-op locator when none is configured
+func newLocator(cfg config.Config) (geoip.Locator, error) {
+	if cfg.GeoIPDBPath == "" {
+		log.Println("GEOIP_DB_PATH is not set; visit locations fall back to the client-reported country")
+		return geoip.Nop(), nil
+	}
+	db, err := geoip.Open(cfg.GeoIPDBPath)
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// newNotifier returns an SMTP-backed notifier, or a no-op one when SMTP is not configured
+func newNotifier(cfg config.Config) (notify.Notifier, error) {
+	if cfg.SMTPHost == "" {
+		log.Println("SMTP_HOST is not set; contact form emails are disabled")
+		return notify.Nop(), nil
+	}
+
+	mailer, err := notify.NewSMTPMailer(notify.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := notify.LoadTemplates(cfg.EmailTemplateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return notify.NewContactNotifier(notify.ContactConfig{
+		OwnerEmail: cfg.ContactNotifyEmail,
+		AutoReply:  cfg.ContactAutoReply,
+		SiteName:   cfg.SiteName,
+		SiteURL:    cfg.SiteURL,
+	}, templates, notify.NewQueue(mailer, 100)), nil
+}
+
+// openRepositories connects the configured storage backend and returns its repositories
+func openRepositories(cfg config.Config) (*repository.Repositories, error) {
+	if cfg.StorageDriver == database.DriverMemory {
+		log.Println("Using in-memory storage; data is lost on restart")
+		return repository.NewMemory(), nil
 	}
 
-	log.Printf("Starting server on port %s", port)
-	log.Fatal(http.ListenAndServe(":"+port, router))
+	db, dialect, err := database.InitDB(cfg.StorageDriver, cfg.StorageDSN())
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewSQL(db, dialect), nil
 }
 
 // @Summary Health check
@@ -134,4 +398,4 @@ func healthCheck(c *gin.Context) {
 		"timestamp": time.Now().UTC(),
 		"version":   "1.0.0",
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// APIKey is a hashed credential for calling mutating endpoints
+type APIKey struct {
+	ID         string     `json:"id" example:"0b6f2c1e-4d3a-4b8e-9f7a-1c2d3e4f5a6b"`
+	UserID     string     `json:"user_id,omitempty" example:"5d1f3a2b-8c4e-4f6a-9b7d-2e1c0a3f4b5d"`
+	Name       string     `json:"name" example:"deploy-bot"`
+	Prefix     string     `json:"prefix" example:"pk_3f9a1c2e"`
+	KeyHash    string     `json:"-"`
+	CreatedAt  time.Time  `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2024-01-02T00:00:00Z"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
@@ -1,30 +1,120 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Contact message statuses
+const (
+	ContactStatusUnread   = "unread"
+	ContactStatusRead     = "read"
+	ContactStatusReplied  = "replied"
+	ContactStatusArchived = "archived"
+	ContactStatusSpam     = "spam"
+)
+
+// ErrInvalidStatusTransition is returned when a contact message cannot move
+// from its current status to the requested one
+var ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+// contactTransitions lists the statuses each status may move to
+var contactTransitions = map[string][]string{
+	ContactStatusUnread:   {ContactStatusRead, ContactStatusReplied, ContactStatusArchived, ContactStatusSpam},
+	ContactStatusRead:     {ContactStatusUnread, ContactStatusReplied, ContactStatusArchived, ContactStatusSpam},
+	ContactStatusReplied:  {ContactStatusArchived},
+	ContactStatusArchived: {ContactStatusRead},
+	ContactStatusSpam:     {ContactStatusRead, ContactStatusArchived},
+}
+
+// IsContactStatus reports whether status is a known contact message status
+func IsContactStatus(status string) bool {
+	_, ok := contactTransitions[status]
+	return ok
+}
 
 // ContactMessage represents a contact form submission
 type ContactMessage struct {
-	ID        int       `json:"id" example:"1"`
-	Name      string    `json:"name" example:"John Doe" binding:"required"`
-	Email     string    `json:"email" example:"john@example.com" binding:"required,email"`
-	Subject   string    `json:"subject" example:"Project Inquiry" binding:"required"`
-	Message   string    `json:"message" example:"I would like to discuss a project opportunity" binding:"required"`
-	Status    string    `json:"status" example:"unread"` // unread, read, replied
-	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	ID        string     `json:"id" example:"8b2e4c1d-5a3f-4d7e-b9c0-1f6a2e8d3c45"`
+	Name      string     `json:"name" example:"John Doe" binding:"required"`
+	Email     string     `json:"email" example:"john@example.com" binding:"required,email"`
+	Subject   string     `json:"subject" example:"Project Inquiry" binding:"required"`
+	Message   string     `json:"message" example:"I would like to discuss a project opportunity" binding:"required"`
+	Status    string     `json:"status" example:"unread"` // unread, read, replied, archived, spam
+	CreatedAt time.Time  `json:"created_at" example:"2024-01-01T00:00:00Z"`
 	ReadAt    *time.Time `json:"read_at,omitempty" example:"2024-01-01T01:00:00Z"`
+	RepliedAt *time.Time `json:"replied_at,omitempty" example:"2024-01-01T02:00:00Z"`
+
+	// Submission metadata and spam assessment, visible to admins
+	IPAddress   string   `json:"ip_address,omitempty" example:"203.0.113.7"`
+	UserAgent   string   `json:"user_agent,omitempty" example:"Mozilla/5.0"`
+	SpamScore   int      `json:"spam_score" example:"0"`
+	SpamReasons []string `json:"spam_reasons,omitempty" example:"too_many_links"`
+}
+
+// TransitionTo moves the message to status, stamping read_at the first time
+// it leaves unread and replied_at when it is answered. Moving to the current
+// status is a no-op.
+func (m *ContactMessage) TransitionTo(status string, at time.Time) error {
+	if status == m.Status {
+		return nil
+	}
+
+	allowed := false
+	for _, next := range contactTransitions[m.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, m.Status, status)
+	}
+
+	if status != ContactStatusUnread && m.ReadAt == nil {
+		m.ReadAt = &at
+	}
+	if status == ContactStatusUnread {
+		m.ReadAt = nil
+	}
+	if status == ContactStatusReplied {
+		m.RepliedAt = &at
+	}
+	m.Status = status
+	return nil
 }
 
 // ContactFormRequest represents the request body for contact form submission
 type ContactFormRequest struct {
-	Name    string `json:"name" binding:"required" example:"John Doe"`
-	Email   string `json:"email" binding:"required,email" example:"john@example.com"`
-	Subject string `json:"subject" binding:"required" example:"Project Inquiry"`
-	Message string `json:"message" binding:"required" example:"I would like to discuss a project opportunity"`
+	Name    string `json:"name" binding:"required,max=100" example:"John Doe"`
+	Email   string `json:"email" binding:"required,email,max=254" example:"john@example.com"`
+	Subject string `json:"subject" binding:"required,max=200" example:"Project Inquiry"`
+	Message string `json:"message" binding:"required,max=5000" example:"I would like to discuss a project opportunity"`
+
+	// Website is a honeypot: hidden from people, so only bots fill it in
+	Website string `json:"website,omitempty" example:""`
+	// FormToken comes from GET /contact/token and proves the form was not submitted instantly
+	FormToken string `json:"form_token,omitempty" example:"1704067200.3q2-7wAbc"`
+	// CaptchaToken is the response from the configured CAPTCHA widget
+	CaptchaToken string `json:"captcha_token,omitempty" example:"0.AbCdEf"`
+}
+
+// UpdateContactStatusRequest represents the request body for changing a message's status
+type UpdateContactStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=unread read replied archived spam" example:"replied"`
+}
+
+// BulkUpdateContactStatusRequest represents the request body for changing the status of several messages
+type BulkUpdateContactStatusRequest struct {
+	IDs    []string `json:"ids" binding:"required,min=1,max=100" example:"8b2e4c1d-5a3f-4d7e-b9c0-1f6a2e8d3c45"`
+	Status string   `json:"status" binding:"required,oneof=unread read replied archived spam" example:"archived"`
 }
 
 // Skill represents a technical skill
 type Skill struct {
-	ID          int    `json:"id" example:"1"`
+	ID          string `json:"id" example:"c4d8e2f1-3b6a-4c9d-8e7f-5a1b2c3d4e5f"`
+	UserID      string `json:"user_id,omitempty" example:"5d1f3a2b-8c4e-4f6a-9b7d-2e1c0a3f4b5d"`
 	Name        string `json:"name" example:"Go" binding:"required"`
 	Category    string `json:"category" example:"backend" binding:"required"`
 	Level       string `json:"level" example:"expert"` // beginner, intermediate, advanced, expert
@@ -37,6 +127,8 @@ type Skill struct {
 
 // AddSkillRequest represents the request body for adding a skill
 type AddSkillRequest struct {
+	// UserID assigns the owner; only admins may set it to another user
+	UserID      string `json:"user_id,omitempty" example:"5d1f3a2b-8c4e-4f6a-9b7d-2e1c0a3f4b5d"`
 	Name        string `json:"name" binding:"required" example:"Python"`
 	Category    string `json:"category" binding:"required" example:"backend"`
 	Level       string `json:"level" binding:"required" example:"advanced"`
@@ -45,4 +137,4 @@ type AddSkillRequest struct {
 	Icon        string `json:"icon,omitempty" example:"https://example.com/python-icon.svg"`
 	Color       string `json:"color,omitempty" example:"#3776AB"`
 	Description string `json:"description,omitempty" example:"Data analysis and web development"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestContactMessageTransitionTo(t *testing.T) {
+	earlier := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	at := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		from        string
+		readAt      *time.Time
+		to          string
+		wantErr     bool
+		wantReadAt  *time.Time
+		wantReplied bool
+	}{
+		{name: "unread to read", from: ContactStatusUnread, to: ContactStatusRead, wantReadAt: &at},
+		{name: "unread to replied", from: ContactStatusUnread, to: ContactStatusReplied, wantReadAt: &at, wantReplied: true},
+		{name: "unread to spam", from: ContactStatusUnread, to: ContactStatusSpam, wantReadAt: &at},
+		{name: "read keeps the first read time", from: ContactStatusRead, readAt: &earlier, to: ContactStatusArchived, wantReadAt: &earlier},
+		{name: "read to unread clears the read time", from: ContactStatusRead, readAt: &earlier, to: ContactStatusUnread},
+		{name: "replied to archived", from: ContactStatusReplied, readAt: &earlier, to: ContactStatusArchived, wantReadAt: &earlier},
+		{name: "archived to read", from: ContactStatusArchived, readAt: &earlier, to: ContactStatusRead, wantReadAt: &earlier},
+		{name: "spam to read", from: ContactStatusSpam, readAt: &earlier, to: ContactStatusRead, wantReadAt: &earlier},
+		{name: "same status is a no-op", from: ContactStatusRead, readAt: &earlier, to: ContactStatusRead, wantReadAt: &earlier},
+		{name: "replied to unread", from: ContactStatusReplied, readAt: &earlier, to: ContactStatusUnread, wantErr: true, wantReadAt: &earlier},
+		{name: "archived to spam", from: ContactStatusArchived, readAt: &earlier, to: ContactStatusSpam, wantErr: true, wantReadAt: &earlier},
+		{name: "unknown status", from: ContactStatusUnread, to: "deleted", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := ContactMessage{Status: tt.from, ReadAt: tt.readAt}
+			err := message.TransitionTo(tt.to, at)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidStatusTransition) {
+					t.Fatalf("got %v, want ErrInvalidStatusTransition", err)
+				}
+				if message.Status != tt.from {
+					t.Errorf("status changed to %q on a rejected transition", message.Status)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if message.Status != tt.to {
+					t.Errorf("status = %q, want %q", message.Status, tt.to)
+				}
+			}
+
+			switch {
+			case tt.wantReadAt == nil && message.ReadAt != nil:
+				t.Errorf("read_at = %v, want nil", *message.ReadAt)
+			case tt.wantReadAt != nil && (message.ReadAt == nil || !message.ReadAt.Equal(*tt.wantReadAt)):
+				t.Errorf("read_at = %v, want %v", message.ReadAt, *tt.wantReadAt)
+			}
+			if replied := message.RepliedAt != nil; replied != tt.wantReplied {
+				t.Errorf("replied_at set = %v, want %v", replied, tt.wantReplied)
+			}
+		})
+	}
+}
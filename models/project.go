@@ -2,26 +2,54 @@ package models
 
 import "time"
 
+// Publication states of a project, separate from its Status. Only published
+// projects are shown publicly; scheduled ones are published at PublishAt.
+const (
+	PublicationDraft     = "draft"
+	PublicationScheduled = "scheduled"
+	PublicationPublished = "published"
+	PublicationArchived  = "archived"
+)
+
+// IsPublication reports whether state is a known publication state
+func IsPublication(state string) bool {
+	switch state {
+	case PublicationDraft, PublicationScheduled, PublicationPublished, PublicationArchived:
+		return true
+	}
+	return false
+}
+
 // Project represents a portfolio project
 type Project struct {
-	ID          int       `json:"id" example:"1"`
-	Title       string    `json:"title" example:"Portfolio Website" binding:"required"`
-	Description string    `json:"description" example:"A responsive portfolio website built with Flutter" binding:"required"`
-	TechStack   []string  `json:"tech_stack" example:"Flutter,Dart,GitHub Pages"`
-	Status      string    `json:"status" example:"completed" binding:"required"`
-	Featured    bool      `json:"featured" example:"true"`
-	LiveURL     string    `json:"live_url,omitempty" example:"https://johndoe.github.io/portfolio"`
-	GithubURL   string    `json:"github_url,omitempty" example:"https://github.com/johndoe/portfolio"`
-	ImageURL    string    `json:"image_url,omitempty" example:"https://example.com/project-image.jpg"`
-	StartDate   time.Time `json:"start_date" example:"2024-01-01T00:00:00Z"`
+	ID          string     `json:"id" example:"3f1c2a9e-7b4d-4e8a-9c61-2d5b8f0e4a17"`
+	Slug        string     `json:"slug" example:"portfolio-website"`
+	UserID      string     `json:"user_id,omitempty" example:"5d1f3a2b-8c4e-4f6a-9b7d-2e1c0a3f4b5d"`
+	Title       string     `json:"title" example:"Portfolio Website" binding:"required"`
+	Description string     `json:"description" example:"A responsive portfolio website built with Flutter" binding:"required"`
+	TechStack   []string   `json:"tech_stack" example:"Flutter,Dart,GitHub Pages"`
+	Status      string     `json:"status" example:"completed" binding:"required"`
+	Featured    bool       `json:"featured" example:"true"`
+	LiveURL     string     `json:"live_url,omitempty" example:"https://johndoe.github.io/portfolio"`
+	GithubURL   string     `json:"github_url,omitempty" example:"https://github.com/johndoe/portfolio"`
+	ImageURL    string     `json:"image_url,omitempty" example:"https://example.com/project-image.jpg"`
+	StartDate   time.Time  `json:"start_date" example:"2024-01-01T00:00:00Z"`
 	EndDate     *time.Time `json:"end_date,omitempty" example:"2024-02-01T00:00:00Z"`
-	CreatedAt   time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt   time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	Publication string     `json:"publication" example:"published"`
+	PublishAt   *time.Time `json:"publish_at,omitempty" example:"2024-02-01T09:00:00Z"`
+	PublishedAt *time.Time `json:"published_at,omitempty" example:"2024-02-01T09:00:00Z"`
+	CreatedAt   time.Time  `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time  `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	// Engagement is included on request with include=engagement
+	Engagement *ProjectEngagement `json:"engagement,omitempty"`
 }
 
 // CreateProjectRequest represents the request body for creating a project
 type CreateProjectRequest struct {
+	// UserID assigns the owner; only admins may set it to another user
+	UserID      string     `json:"user_id,omitempty" example:"5d1f3a2b-8c4e-4f6a-9b7d-2e1c0a3f4b5d"`
 	Title       string     `json:"title" binding:"required" example:"New Project"`
+	Slug        string     `json:"slug,omitempty" example:"new-project"`
 	Description string     `json:"description" binding:"required" example:"Project description"`
 	TechStack   []string   `json:"tech_stack" example:"Go,React,PostgreSQL"`
 	Status      string     `json:"status" binding:"required" example:"in-progress"`
@@ -31,11 +59,14 @@ type CreateProjectRequest struct {
 	ImageURL    string     `json:"image_url,omitempty" example:"https://example.com/image.jpg"`
 	StartDate   time.Time  `json:"start_date" example:"2024-01-01T00:00:00Z"`
 	EndDate     *time.Time `json:"end_date,omitempty" example:"2024-02-01T00:00:00Z"`
+	Publication string     `json:"publication,omitempty" binding:"omitempty,oneof=draft scheduled published archived" example:"draft"`
+	PublishAt   *time.Time `json:"publish_at,omitempty" example:"2024-02-01T09:00:00Z"`
 }
 
 // UpdateProjectRequest represents the request body for updating a project
 type UpdateProjectRequest struct {
 	Title       *string    `json:"title,omitempty" example:"Updated Project Title"`
+	Slug        *string    `json:"slug,omitempty" example:"updated-project"`
 	Description *string    `json:"description,omitempty" example:"Updated description"`
 	TechStack   *[]string  `json:"tech_stack,omitempty" example:"Go,React,PostgreSQL,Docker"`
 	Status      *string    `json:"status,omitempty" example:"completed"`
@@ -45,4 +76,6 @@ type UpdateProjectRequest struct {
 	ImageURL    *string    `json:"image_url,omitempty" example:"https://example.com/updated-image.jpg"`
 	StartDate   *time.Time `json:"start_date,omitempty" example:"2024-01-15T00:00:00Z"`
 	EndDate     *time.Time `json:"end_date,omitempty" example:"2024-03-01T00:00:00Z"`
-}
\ No newline at end of file
+	Publication *string    `json:"publication,omitempty" binding:"omitempty,oneof=draft scheduled published archived" example:"scheduled"`
+	PublishAt   *time.Time `json:"publish_at,omitempty" example:"2024-03-01T09:00:00Z"`
+}
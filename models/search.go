@@ -0,0 +1,24 @@
+package models
+
+// Types of search results
+const (
+	SearchTypeProject = "project"
+	SearchTypeSkill   = "skill"
+	SearchTypeUser    = "user"
+)
+
+// SearchTypes lists every type of search result
+var SearchTypes = []string{SearchTypeProject, SearchTypeSkill, SearchTypeUser}
+
+// SearchResult is a project, skill or user profile matching a search
+type SearchResult struct {
+	Type  string `json:"type" example:"project"`
+	ID    string `json:"id" example:"3f1c2a9e-7b4d-4e8a-9c61-2d5b8f0e4a17"`
+	Title string `json:"title" example:"Portfolio API Server"`
+	// Snippet is HTML-escaped text with the matching words in <mark> tags
+	Snippet string `json:"snippet" example:"Go REST API with <mark>Supabase</mark> integration"`
+	// Rank orders results by relevance; it only compares within one search
+	Rank float64 `json:"rank" example:"0.61"`
+	// URL is the API path of the matching record
+	URL string `json:"url" example:"/api/v1/projects/3f1c2a9e-7b4d-4e8a-9c61-2d5b8f0e4a17"`
+}
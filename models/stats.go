@@ -2,16 +2,33 @@ package models
 
 import "time"
 
-// ViewStats represents portfolio view statistics
+// ViewStats represents portfolio view statistics. Visitor IDs rotate daily,
+// so a visitor who returns on another day is counted again. BounceRate is the
+// percentage of sessions that viewed a single page; AvgSessionDuration is in
+// seconds up to the last heartbeat or leave event.
 type ViewStats struct {
-	TotalViews    int            `json:"total_views" example:"1250"`
-	UniqueVisitors int           `json:"unique_visitors" example:"950"`
-	ViewsToday    int            `json:"views_today" example:"45"`
-	ViewsThisWeek int            `json:"views_this_week" example:"320"`
-	ViewsThisMonth int           `json:"views_this_month" example:"1100"`
-	TopPages      []PageStat     `json:"top_pages"`
-	ViewsByCountry []CountryStat `json:"views_by_country"`
-	ViewsOverTime []TimeStat     `json:"views_over_time"`
+	From               time.Time     `json:"from" example:"2024-01-01T00:00:00+09:00"`
+	To                 time.Time     `json:"to" example:"2024-01-31T00:00:00+09:00"`
+	Granularity        string        `json:"granularity" example:"day"`
+	Timezone           string        `json:"timezone" example:"Asia/Seoul"`
+	TotalViews         int           `json:"total_views" example:"1250"`
+	UniqueVisitors     int           `json:"unique_visitors" example:"950"`
+	ViewsToday         int           `json:"views_today" example:"45"`
+	ViewsThisWeek      int           `json:"views_this_week" example:"320"`
+	ViewsThisMonth     int           `json:"views_this_month" example:"1100"`
+	Sessions           int           `json:"sessions" example:"1010"`
+	BounceRate         float64       `json:"bounce_rate" example:"42.5"`
+	PagesPerSession    float64       `json:"pages_per_session" example:"2.3"`
+	AvgSessionDuration float64       `json:"avg_session_duration" example:"95.4"`
+	TopEntryPages      []PageStat    `json:"top_entry_pages"`
+	TopExitPages       []PageStat    `json:"top_exit_pages"`
+	TopPages           []PageStat    `json:"top_pages"`
+	ViewsByCountry     []CountryStat `json:"views_by_country"`
+	ViewsByCity        []CityStat    `json:"views_by_city"`
+	ViewsByBrowser     []BrowserStat `json:"views_by_browser"`
+	ViewsByOS          []OSStat      `json:"views_by_os"`
+	ViewsByDevice      []DeviceStat  `json:"views_by_device"`
+	ViewsOverTime      []TimeStat    `json:"views_over_time"`
 }
 
 // PageStat represents statistics for a specific page
@@ -22,32 +39,74 @@ type PageStat struct {
 
 // CountryStat represents statistics by country
 type CountryStat struct {
-	Country string `json:"country" example:"South Korea"`
+	// Country is an ISO 3166-1 alpha-2 code, or "Unknown"
+	Country string `json:"country" example:"KR"`
 	Views   int    `json:"views" example:"680"`
 }
 
+// CityStat represents statistics by city
+type CityStat struct {
+	City    string `json:"city" example:"Seoul"`
+	Country string `json:"country" example:"KR"`
+	Views   int    `json:"views" example:"410"`
+}
+
+// BrowserStat represents statistics by browser family
+type BrowserStat struct {
+	Browser string `json:"browser" example:"Chrome"`
+	Views   int    `json:"views" example:"720"`
+}
+
+// OSStat represents statistics by operating system
+type OSStat struct {
+	OS    string `json:"os" example:"Windows"`
+	Views int    `json:"views" example:"510"`
+}
+
+// DeviceStat represents statistics by device type
+type DeviceStat struct {
+	DeviceType string `json:"device_type" example:"mobile"`
+	Views      int    `json:"views" example:"390"`
+}
+
 // TimeStat represents views over time
 type TimeStat struct {
-	Date  string `json:"date" example:"2024-01-01"`
-	Views int    `json:"views" example:"85"`
+	// Date labels the bucket start: 2024-01-01T09:00 (hour), 2024-01-01 (day, week) or 2024-01 (month)
+	Date     string `json:"date" example:"2024-01-01"`
+	Views    int    `json:"views" example:"85"`
+	Visitors int    `json:"visitors" example:"61"`
 }
 
 // ProjectStats represents project-related statistics
 type ProjectStats struct {
-	TotalProjects     int                `json:"total_projects" example:"12"`
-	CompletedProjects int                `json:"completed_projects" example:"10"`
-	FeaturedProjects  int                `json:"featured_projects" example:"5"`
-	TechStackStats    []TechStackStat    `json:"tech_stack_stats"`
+	TotalProjects     int                 `json:"total_projects" example:"12"`
+	CompletedProjects int                 `json:"completed_projects" example:"10"`
+	FeaturedProjects  int                 `json:"featured_projects" example:"5"`
+	TechStackStats    []TechStackStat     `json:"tech_stack_stats"`
 	ProjectsByStatus  []ProjectStatusStat `json:"projects_by_status"`
+	Engagement        []ProjectEngagement `json:"engagement"`
 }
 
 // TechStackStat represents statistics for technology usage
 type TechStackStat struct {
-	Technology string `json:"technology" example:"Go"`
-	Count      int    `json:"count" example:"8"`
+	Technology string  `json:"technology" example:"Go"`
+	Count      int     `json:"count" example:"8"`
 	Percentage float64 `json:"percentage" example:"66.7"`
 }
 
+// ProjectEngagement counts the views of a project's page and the clicks on its
+// live site and GitHub links. CTR is the percentage of views followed by a click.
+type ProjectEngagement struct {
+	ProjectID    string  `json:"project_id" example:"3f1c2a9e-7b4d-4e8a-9c61-2d5b8f0e4a17"`
+	Title        string  `json:"title,omitempty" example:"Portfolio Website"`
+	Views        int     `json:"views" example:"240"`
+	Visitors     int     `json:"visitors" example:"180"`
+	LiveClicks   int     `json:"live_clicks" example:"31"`
+	GithubClicks int     `json:"github_clicks" example:"17"`
+	Clicks       int     `json:"clicks" example:"48"`
+	CTR          float64 `json:"ctr" example:"20"`
+}
+
 // ProjectStatusStat represents project statistics by status
 type ProjectStatusStat struct {
 	Status string `json:"status" example:"completed"`
@@ -56,19 +115,144 @@ type ProjectStatusStat struct {
 
 // VisitRequest represents a visit tracking request
 type VisitRequest struct {
-	Page      string `json:"page" binding:"required" example:"/projects"`
-	UserAgent string `json:"user_agent,omitempty" example:"Mozilla/5.0..."`
-	Country   string `json:"country,omitempty" example:"KR"`
-	Referrer  string `json:"referrer,omitempty" example:"https://google.com"`
+	Page string `json:"page" form:"page" binding:"required,max=255" example:"/projects"`
+	// Country is used only when the server cannot locate the client's IP address
+	Country  string `json:"country,omitempty" form:"country" example:"KR"`
+	Referrer string `json:"referrer,omitempty" form:"referrer" example:"https://google.com"`
+	// ProjectID links the visit to a project; pages such as /projects/{id} are linked without it
+	ProjectID string `json:"project_id,omitempty" form:"project_id" binding:"omitempty,uuid" example:"3f1c2a9e-7b4d-4e8a-9c61-2d5b8f0e4a17"`
+}
+
+// ReferrerStats breaks visits down by channel and referring host
+type ReferrerStats struct {
+	From      time.Time      `json:"from" example:"2024-01-01T00:00:00+09:00"`
+	To        time.Time      `json:"to" example:"2024-01-31T00:00:00+09:00"`
+	Timezone  string         `json:"timezone" example:"Asia/Seoul"`
+	Channels  []ChannelStat  `json:"channels"`
+	Referrers []ReferrerStat `json:"referrers"`
+}
+
+// ChannelStat represents statistics for a channel: search, social, email, direct or other
+type ChannelStat struct {
+	Channel  string `json:"channel" example:"social"`
+	Views    int    `json:"views" example:"310"`
+	Visitors int    `json:"visitors" example:"120"`
+}
+
+// ReferrerStat represents statistics for a referring host
+type ReferrerStat struct {
+	Host     string `json:"host" example:"linkedin.com"`
+	Channel  string `json:"channel" example:"social"`
+	Views    int    `json:"views" example:"180"`
+	Visitors int    `json:"visitors" example:"75"`
+}
+
+// CampaignStats breaks visits down by UTM campaign
+type CampaignStats struct {
+	From      time.Time      `json:"from" example:"2024-01-01T00:00:00+09:00"`
+	To        time.Time      `json:"to" example:"2024-01-31T00:00:00+09:00"`
+	Timezone  string         `json:"timezone" example:"Asia/Seoul"`
+	Campaigns []CampaignStat `json:"campaigns"`
+}
+
+// CampaignStat represents statistics for a utm_source/utm_medium/utm_campaign combination
+type CampaignStat struct {
+	Source   string `json:"source" example:"linkedin"`
+	Medium   string `json:"medium" example:"social"`
+	Campaign string `json:"campaign" example:"job-search"`
+	Views    int    `json:"views" example:"64"`
+	Visitors int    `json:"visitors" example:"41"`
+}
+
+// VisitEventRequest reports that a recorded visit's page is still open
+// (heartbeat) or was closed (leave)
+type VisitEventRequest struct {
+	VisitID string `json:"visit_id" form:"visit_id" binding:"required,uuid" example:"e7a1b3c5-9d2f-4e6a-8b0c-3d5f7a9b1c2e"`
+	Type    string `json:"type" form:"type" binding:"required,oneof=heartbeat leave" example:"heartbeat"`
 }
 
 // Visit represents a recorded visit
 type Visit struct {
-	ID        int       `json:"id" example:"1"`
-	Page      string    `json:"page" example:"/projects"`
-	UserAgent string    `json:"user_agent,omitempty"`
-	Country   string    `json:"country,omitempty" example:"KR"`
+	ID        string `json:"id" example:"e7a1b3c5-9d2f-4e6a-8b0c-3d5f7a9b1c2e"`
+	Page      string `json:"page" example:"/projects"`
+	UserAgent string `json:"user_agent,omitempty"`
+	Country   string `json:"country,omitempty" example:"KR"`
+	City      string `json:"city,omitempty" example:"Seoul"`
+	Referrer  string `json:"referrer,omitempty"`
+	// VisitorID is a salted hash of IP address and user agent that rotates
+	// daily; raw IP addresses are never stored
+	VisitorID string `json:"visitor_id,omitempty" example:"3f2a9c0d5b7e41a8c6d2e0f9b1a4c7d3"`
+	// Browser, BrowserVersion, OS and DeviceType classify UserAgent
+	Browser        string `json:"browser,omitempty" example:"Chrome"`
+	BrowserVersion string `json:"browser_version,omitempty" example:"120"`
+	OS             string `json:"os,omitempty" example:"macOS"`
+	DeviceType     string `json:"device_type,omitempty" example:"desktop"`
+	// IsBot flags crawlers, monitors, automated browsers and excluded networks
+	IsBot     bool   `json:"is_bot"`
+	BotReason string `json:"bot_reason,omitempty" example:"user_agent"`
+	// ReferrerHost and Channel classify Referrer; the UTM fields come from the page URL
+	ReferrerHost string    `json:"referrer_host,omitempty" example:"linkedin.com"`
+	Channel      string    `json:"channel,omitempty" example:"social"`
+	UTMSource    string    `json:"utm_source,omitempty" example:"linkedin"`
+	UTMMedium    string    `json:"utm_medium,omitempty" example:"social"`
+	UTMCampaign  string    `json:"utm_campaign,omitempty" example:"job-search"`
+	ProjectID    string    `json:"project_id,omitempty" example:"3f1c2a9e-7b4d-4e8a-9c61-2d5b8f0e4a17"`
+	CreatedAt    time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	// EndedAt is the last heartbeat or leave event on the page
+	EndedAt *time.Time `json:"ended_at,omitempty" example:"2024-01-01T00:02:30Z"`
+}
+
+// Rollup is an hourly or daily aggregate of visits for one dimension value.
+// Visitors counts distinct visitors in the period; NewVisitors counts those
+// first seen that UTC day, so it can be summed across hours. The session
+// fields count sessions that started in the period.
+type Rollup struct {
+	PeriodStart    time.Time `json:"period_start"`
+	IsBot          bool      `json:"is_bot"`
+	Dimension      string    `json:"dimension"`
+	Value          string    `json:"value"`
+	Views          int       `json:"views"`
+	Visitors       int       `json:"visitors"`
+	NewVisitors    int       `json:"new_visitors"`
+	Sessions       int       `json:"sessions"`
+	Bounces        int       `json:"bounces"`
+	SessionPages   int       `json:"session_pages"`
+	SessionSeconds float64   `json:"session_seconds"`
+}
+
+// LiveVisit is a visit pushed to the live feed; it never carries the IP
+// address or visitor ID
+type LiveVisit struct {
+	Page         string    `json:"page" example:"/projects"`
+	Country      string    `json:"country,omitempty" example:"KR"`
+	City         string    `json:"city,omitempty" example:"Seoul"`
+	DeviceType   string    `json:"device_type,omitempty" example:"desktop"`
+	Browser      string    `json:"browser,omitempty" example:"Chrome"`
+	ReferrerHost string    `json:"referrer_host,omitempty" example:"linkedin.com"`
+	Channel      string    `json:"channel,omitempty" example:"social"`
+	At           time.Time `json:"at" example:"2024-01-01T09:30:00Z"`
+}
+
+// LiveActivity is the rolling count of visitors active on the site
+type LiveActivity struct {
+	ActiveVisitors int       `json:"active_visitors" example:"3"`
+	At             time.Time `json:"at" example:"2024-01-01T09:30:00Z"`
+}
+
+// Project link targets followed through /p/:id/:target
+const (
+	ClickTargetLive   = "live"
+	ClickTargetGithub = "github"
+)
+
+// ProjectClick represents a click on a project's live site or GitHub link
+type ProjectClick struct {
+	ID        string    `json:"id" example:"9b2d4f6a-1c3e-4a5b-8d7f-0e2c4a6b8d1f"`
+	ProjectID string    `json:"project_id" example:"3f1c2a9e-7b4d-4e8a-9c61-2d5b8f0e4a17"`
+	Target    string    `json:"target" example:"github"`
+	VisitorID string    `json:"visitor_id,omitempty" example:"3f2a9c0d5b7e41a8c6d2e0f9b1a4c7d3"`
 	Referrer  string    `json:"referrer,omitempty"`
-	IP        string    `json:"ip,omitempty"`
+	IsBot     bool      `json:"is_bot"`
+	BotReason string    `json:"bot_reason,omitempty" example:"user_agent"`
 	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
-}
\ No newline at end of file
+}
@@ -2,41 +2,110 @@ package models
 
 import "time"
 
-// User represents a user in the system
+// Roles stored in users.role
+const (
+	// RoleAdmin manages every user, project and skill
+	RoleAdmin = "admin"
+	// RoleOwner manages their own profile, projects and skills
+	RoleOwner = "owner"
+	// RoleViewer can authenticate but not change portfolio content
+	RoleViewer = "viewer"
+)
+
+// User represents a portfolio owner profile, mirroring the Supabase users table
 type User struct {
-	ID        int       `json:"id" example:"1"`
-	Name      string    `json:"name" example:"John Doe" binding:"required"`
-	Email     string    `json:"email" example:"john@example.com" binding:"required,email"`
-	Role      string    `json:"role" example:"developer" binding:"required"`
-	Avatar    string    `json:"avatar,omitempty" example:"https://example.com/avatar.jpg"`
+	ID        string    `json:"id" example:"5d1f3a2b-8c4e-4f6a-9b7d-2e1c0a3f4b5d"`
+	Name      string    `json:"name" example:"John Doe"`
+	Email     string    `json:"email" example:"john@example.com"`
+	Role      string    `json:"role" example:"owner"`
+	AvatarURL string    `json:"avatar_url,omitempty" example:"https://example.com/avatar.jpg"`
 	Bio       string    `json:"bio,omitempty" example:"Full-stack developer with 5+ years experience"`
 	Website   string    `json:"website,omitempty" example:"https://johndoe.dev"`
 	Location  string    `json:"location,omitempty" example:"Seoul, Korea"`
-	Skills    []string  `json:"skills,omitempty" example:"Go,JavaScript,React"`
+	Skills    []string  `json:"skills" example:"Go,JavaScript,React"`
+	IsPublic  bool      `json:"is_public" example:"true"`
 	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`
 }
 
 // CreateUserRequest represents the request body for creating a user
 type CreateUserRequest struct {
-	Name     string   `json:"name" binding:"required" example:"John Doe"`
-	Email    string   `json:"email" binding:"required,email" example:"john@example.com"`
-	Role     string   `json:"role" binding:"required" example:"developer"`
-	Avatar   string   `json:"avatar,omitempty" example:"https://example.com/avatar.jpg"`
-	Bio      string   `json:"bio,omitempty" example:"Full-stack developer"`
-	Website  string   `json:"website,omitempty" example:"https://johndoe.dev"`
-	Location string   `json:"location,omitempty" example:"Seoul, Korea"`
-	Skills   []string `json:"skills,omitempty" example:"Go,JavaScript"`
+	Name      string   `json:"name" binding:"required" example:"John Doe"`
+	Email     string   `json:"email" binding:"required,email" example:"john@example.com"`
+	Role      string   `json:"role" binding:"required,oneof=admin owner viewer" example:"owner"`
+	AvatarURL string   `json:"avatar_url,omitempty" example:"https://example.com/avatar.jpg"`
+	Bio       string   `json:"bio,omitempty" example:"Full-stack developer"`
+	Website   string   `json:"website,omitempty" example:"https://johndoe.dev"`
+	Location  string   `json:"location,omitempty" example:"Seoul, Korea"`
+	Skills    []string `json:"skills,omitempty" example:"Go,JavaScript"`
+	IsPublic  *bool    `json:"is_public,omitempty" example:"true"`
 }
 
 // UpdateUserRequest represents the request body for updating a user
 type UpdateUserRequest struct {
-	Name     *string   `json:"name,omitempty" example:"Jane Doe"`
-	Email    *string   `json:"email,omitempty" example:"jane@example.com"`
-	Role     *string   `json:"role,omitempty" example:"senior-developer"`
-	Avatar   *string   `json:"avatar,omitempty" example:"https://example.com/new-avatar.jpg"`
-	Bio      *string   `json:"bio,omitempty" example:"Senior full-stack developer"`
-	Website  *string   `json:"website,omitempty" example:"https://janedoe.dev"`
-	Location *string   `json:"location,omitempty" example:"Busan, Korea"`
-	Skills   *[]string `json:"skills,omitempty" example:"Go,JavaScript,React,Vue"`
-}
\ No newline at end of file
+	Name      *string   `json:"name,omitempty" example:"Jane Doe"`
+	Email     *string   `json:"email,omitempty" binding:"omitempty,email" example:"jane@example.com"`
+	Role      *string   `json:"role,omitempty" binding:"omitempty,oneof=admin owner viewer" example:"viewer"`
+	AvatarURL *string   `json:"avatar_url,omitempty" example:"https://example.com/new-avatar.jpg"`
+	Bio       *string   `json:"bio,omitempty" example:"Senior full-stack developer"`
+	Website   *string   `json:"website,omitempty" example:"https://janedoe.dev"`
+	Location  *string   `json:"location,omitempty" example:"Busan, Korea"`
+	Skills    *[]string `json:"skills,omitempty" example:"Go,JavaScript,React,Vue"`
+	IsPublic  *bool     `json:"is_public,omitempty" example:"false"`
+}
+
+// ToUser maps a create request onto a new User; profiles are public unless stated otherwise
+func (r CreateUserRequest) ToUser() User {
+	isPublic := true
+	if r.IsPublic != nil {
+		isPublic = *r.IsPublic
+	}
+
+	skills := r.Skills
+	if skills == nil {
+		skills = []string{}
+	}
+
+	return User{
+		Name:      r.Name,
+		Email:     r.Email,
+		Role:      r.Role,
+		AvatarURL: r.AvatarURL,
+		Bio:       r.Bio,
+		Website:   r.Website,
+		Location:  r.Location,
+		Skills:    skills,
+		IsPublic:  isPublic,
+	}
+}
+
+// ApplyTo copies the provided fields of an update request onto user
+func (r UpdateUserRequest) ApplyTo(user *User) {
+	if r.Name != nil {
+		user.Name = *r.Name
+	}
+	if r.Email != nil {
+		user.Email = *r.Email
+	}
+	if r.Role != nil {
+		user.Role = *r.Role
+	}
+	if r.AvatarURL != nil {
+		user.AvatarURL = *r.AvatarURL
+	}
+	if r.Bio != nil {
+		user.Bio = *r.Bio
+	}
+	if r.Website != nil {
+		user.Website = *r.Website
+	}
+	if r.Location != nil {
+		user.Location = *r.Location
+	}
+	if r.Skills != nil {
+		user.Skills = *r.Skills
+	}
+	if r.IsPublic != nil {
+		user.IsPublic = *r.IsPublic
+	}
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"log"
+
+	"portfolio-api/models"
+)
+
+// ContactConfig configures the contact form emails
+type ContactConfig struct {
+	// OwnerEmail receives a notification for every submission
+	OwnerEmail string
+	// AutoReply sends the submitter a confirmation
+	AutoReply bool
+	SiteName  string
+	SiteURL   string
+}
+
+// contactData is the data passed to the owner notification templates
+type contactData struct {
+	Message  models.ContactMessage
+	SiteName string
+	SiteURL  string
+}
+
+// autoReplyData is the data passed to the auto-reply templates. It carries
+// nothing the sender typed: the auto-reply goes to whatever address the form
+// was given, so echoing the name or subject would let anyone mail their own
+// text, links included, from the site.
+type autoReplyData struct {
+	SiteName string
+	SiteURL  string
+}
+
+// ContactNotifier emails the portfolio owner, and optionally the sender,
+// about contact form submissions
+type ContactNotifier struct {
+	cfg       ContactConfig
+	templates *Templates
+	queue     *Queue
+}
+
+// NewContactNotifier creates a notifier that renders with templates and
+// delivers through queue
+func NewContactNotifier(cfg ContactConfig, templates *Templates, queue *Queue) *ContactNotifier {
+	return &ContactNotifier{cfg: cfg, templates: templates, queue: queue}
+}
+
+// ContactSubmitted queues the owner notification and the auto-reply
+func (n *ContactNotifier) ContactSubmitted(message models.ContactMessage) {
+	data := contactData{Message: message, SiteName: n.cfg.SiteName, SiteURL: n.cfg.SiteURL}
+
+	if n.cfg.OwnerEmail != "" {
+		email, err := n.templates.Render(templateContactOwner, data)
+		if err != nil {
+			log.Printf("Error: failed to render contact notification: %v", err)
+		} else {
+			email.To = []string{n.cfg.OwnerEmail}
+			email.ReplyTo = message.Email
+			n.queue.Enqueue(email)
+		}
+	}
+
+	if n.cfg.AutoReply {
+		email, err := n.templates.Render(templateContactAutoReply, autoReplyData{SiteName: n.cfg.SiteName, SiteURL: n.cfg.SiteURL})
+		if err != nil {
+			log.Printf("Error: failed to render contact auto-reply: %v", err)
+		} else {
+			email.To = []string{message.Email}
+			n.queue.Enqueue(email)
+		}
+	}
+}
+
+// Close drains the delivery queue
+func (n *ContactNotifier) Close(ctx context.Context) error {
+	return n.queue.Close(ctx)
+}
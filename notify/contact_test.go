@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"portfolio-api/models"
+)
+
+// recordingMailer keeps every email it is asked to send
+type recordingMailer struct {
+	mu     sync.Mutex
+	emails []Email
+}
+
+func (m *recordingMailer) Send(ctx context.Context, email Email) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.emails = append(m.emails, email)
+	return nil
+}
+
+func TestContactAutoReplyEchoesNothingTheSenderTyped(t *testing.T) {
+	templates, err := LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mailer := &recordingMailer{}
+	n := NewContactNotifier(ContactConfig{AutoReply: true, SiteName: "Portfolio"}, templates, NewQueue(mailer, 1))
+
+	n.ContactSubmitted(models.ContactMessage{
+		Name:    "Claim your prize at https://phish.example",
+		Email:   "victim@example.com",
+		Subject: "You won",
+		Message: "hello",
+	})
+	if err := n.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mailer.emails) != 1 {
+		t.Fatalf("sent %d emails, want the auto-reply only", len(mailer.emails))
+	}
+	email := mailer.emails[0]
+	if len(email.To) != 1 || email.To[0] != "victim@example.com" {
+		t.Errorf("auto-reply sent to %v", email.To)
+	}
+	for _, part := range []string{email.Subject, email.Text, email.HTML} {
+		if strings.Contains(part, "phish") || strings.Contains(part, "You won") {
+			t.Errorf("auto-reply echoes the submission: %q", part)
+		}
+	}
+}
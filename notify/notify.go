@@ -0,0 +1,41 @@
+// Package notify sends email about events such as contact form submissions
+package notify
+
+import (
+	"context"
+
+	"portfolio-api/models"
+)
+
+// Email is a rendered message ready to hand to a Mailer
+type Email struct {
+	To      []string
+	ReplyTo string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer delivers a single email
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// Notifier reacts to events that someone should hear about by email. Calls
+// never block on delivery.
+type Notifier interface {
+	ContactSubmitted(message models.ContactMessage)
+	// Close waits for queued email to be delivered until ctx is done
+	Close(ctx context.Context) error
+}
+
+// Nop returns a Notifier that discards every event
+func Nop() Notifier {
+	return nopNotifier{}
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) ContactSubmitted(models.ContactMessage) {}
+
+func (nopNotifier) Close(context.Context) error { return nil }
@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Queue delivers email in the background, retrying failed sends with
+// exponential backoff so callers never wait on the mail server
+type Queue struct {
+	mailer      Mailer
+	jobs        chan Email
+	maxAttempts int
+	backoff     time.Duration
+	timeout     time.Duration
+
+	mu     sync.Mutex
+	closed bool
+	quit   chan struct{}
+	stop   sync.Once
+	done   chan struct{}
+}
+
+// NewQueue starts a delivery worker holding up to size pending emails
+func NewQueue(mailer Mailer, size int) *Queue {
+	q := &Queue{
+		mailer:      mailer,
+		jobs:        make(chan Email, size),
+		maxAttempts: 5,
+		backoff:     2 * time.Second,
+		timeout:     30 * time.Second,
+		quit:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Enqueue schedules email for delivery. It reports false, dropping the email,
+// when the queue is full or closed.
+func (q *Queue) Enqueue(email Email) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return false
+	}
+	select {
+	case q.jobs <- email:
+		return true
+	default:
+		log.Printf("Warning: email queue is full, dropping %q", email.Subject)
+		return false
+	}
+}
+
+// Close stops accepting email and waits for the queue to drain. Pending
+// retries are abandoned once ctx is done. Close may be called again, for
+// instance after an earlier call timed out.
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		q.stop.Do(func() { close(q.quit) })
+		<-q.done
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) run() {
+	defer close(q.done)
+	for email := range q.jobs {
+		q.deliver(email)
+	}
+}
+
+// deliver sends one email, backing off between failed attempts
+func (q *Queue) deliver(email Email) {
+	wait := q.backoff
+	for attempt := 1; attempt <= q.maxAttempts; attempt++ {
+		select {
+		case <-q.quit:
+			log.Printf("Warning: shutting down, dropped email %q", email.Subject)
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
+		err := q.mailer.Send(ctx, email)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		if attempt == q.maxAttempts {
+			log.Printf("Error: giving up on email %q after %d attempts: %v", email.Subject, attempt, err)
+			return
+		}
+		log.Printf("Warning: email %q failed (attempt %d/%d), retrying in %s: %v",
+			email.Subject, attempt, q.maxAttempts, wait, err)
+
+		select {
+		case <-time.After(wait):
+			wait *= 2
+		case <-q.quit:
+			log.Printf("Warning: shutting down, dropped email %q", email.Subject)
+			return
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// failingMailer fails every send, keeping the queue busy retrying
+type failingMailer struct{}
+
+func (failingMailer) Send(ctx context.Context, email Email) error {
+	return errors.New("mail server unavailable")
+}
+
+func TestQueueCloseTwiceAfterTimeout(t *testing.T) {
+	q := NewQueue(failingMailer{}, 1)
+	q.backoff = time.Hour
+	q.Enqueue(Email{Subject: "Hello"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("first Close: got %v, want a deadline error", err)
+	}
+
+	// Later calls with a done context must not close the queue again
+	for i := 0; i < 20; i++ {
+		q.Close(ctx)
+	}
+
+	if q.Enqueue(Email{Subject: "Late"}) {
+		t.Error("Enqueue accepted an email after Close")
+	}
+}
@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Email kinds; each has a <name>.txt.tmpl defining a "subject" block and a <name>.html.tmpl
+const (
+	templateContactOwner     = "contact_owner"
+	templateContactAutoReply = "contact_autoreply"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// Templates renders the subject, text and HTML bodies of each email kind
+type Templates struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// LoadTemplates parses the email templates in dir, or the built-in ones when
+// dir is empty, so mistakes surface at startup rather than on first send
+func LoadTemplates(dir string) (*Templates, error) {
+	var fsys fs.FS
+	if dir == "" {
+		sub, err := fs.Sub(builtinTemplates, "templates")
+		if err != nil {
+			return nil, err
+		}
+		fsys = sub
+	} else {
+		fsys = os.DirFS(dir)
+	}
+
+	t := &Templates{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+	for _, name := range []string{templateContactOwner, templateContactAutoReply} {
+		text, err := texttemplate.ParseFS(fsys, name+".txt.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s text template: %v", name, err)
+		}
+		if text.Lookup("subject") == nil {
+			return nil, fmt.Errorf("%s.txt.tmpl does not define a \"subject\" block", name)
+		}
+		html, err := htmltemplate.ParseFS(fsys, name+".html.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s HTML template: %v", name, err)
+		}
+		t.text[name] = text
+		t.html[name] = html
+	}
+	return t, nil
+}
+
+// Render executes the named templates with data
+func (t *Templates) Render(name string, data interface{}) (Email, error) {
+	text, ok := t.text[name]
+	if !ok {
+		return Email{}, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, body, html bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Email{}, err
+	}
+	if err := text.ExecuteTemplate(&body, name+".txt.tmpl", data); err != nil {
+		return Email{}, err
+	}
+	if err := t.html[name].ExecuteTemplate(&html, name+".html.tmpl", data); err != nil {
+		return Email{}, err
+	}
+
+	return Email{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    body.String(),
+		HTML:    html.String(),
+	}, nil
+}
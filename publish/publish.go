@@ -0,0 +1,51 @@
+// Package publish publishes scheduled projects in the background once their
+// publish time has passed
+package publish
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"portfolio-api/repository"
+)
+
+// Scheduler publishes projects scheduled for a time that has passed
+type Scheduler struct {
+	projects repository.ProjectRepository
+}
+
+// NewScheduler creates a Scheduler for the projects
+func NewScheduler(projects repository.ProjectRepository) *Scheduler {
+	return &Scheduler{projects: projects}
+}
+
+// Run publishes due projects immediately and then every interval until ctx
+// is done. A project is published at most one interval late.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunOnce(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("Warning: scheduled publishing failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce publishes the projects scheduled for now or earlier
+func (s *Scheduler) RunOnce(ctx context.Context, now time.Time) error {
+	published, err := s.projects.PublishDue(ctx, now)
+	if err != nil {
+		return err
+	}
+	if published > 0 {
+		log.Printf("Published %d scheduled project(s)", published)
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+// Package referrer classifies where visits come from: the referring host,
+// its channel and any UTM campaign parameters
+package referrer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Channels a visit can come from
+const (
+	Search = "search"
+	Social = "social"
+	Email  = "email"
+	Direct = "direct"
+	Other  = "other"
+)
+
+// Rules lists the hosts of each channel. A host matches a rule when it is
+// the rule's domain or a subdomain of it; a rule ending in ".*" matches any
+// top-level domain, e.g. "google.*" matches google.com and google.co.kr.
+type Rules struct {
+	Search []string `json:"search"`
+	Social []string `json:"social"`
+	Email  []string `json:"email"`
+}
+
+// DefaultRules covers the search engines, social networks and webmail
+// services our visitors use
+func DefaultRules() Rules {
+	return Rules{
+		Search: []string{
+			"google.*", "bing.com", "duckduckgo.com", "search.yahoo.com", "yandex.*",
+			"baidu.com", "naver.com", "search.naver.com", "daum.net", "search.daum.net",
+			"ecosia.org", "search.brave.com", "startpage.com",
+		},
+		Social: []string{
+			"linkedin.com", "lnkd.in", "com.linkedin.android", "github.com", "twitter.com", "x.com", "t.co",
+			"facebook.com", "fb.com", "l.facebook.com", "instagram.com", "threads.net",
+			"reddit.com", "news.ycombinator.com", "youtube.com", "medium.com", "dev.to",
+			"velog.io", "tistory.com", "blog.naver.com", "cafe.naver.com", "kakao.com",
+			"mastodon.social", "bsky.app", "discord.com", "slack.com",
+		},
+		Email: []string{
+			"mail.google.com", "outlook.live.com", "outlook.office.com", "outlook.office365.com",
+			"mail.yahoo.com", "mail.naver.com", "mail.daum.net", "mail.proton.me",
+		},
+	}
+}
+
+// LoadRules returns the default rules extended with the lists in a JSON file
+// shaped like Rules; an empty path returns the defaults
+func LoadRules(path string) (Rules, error) {
+	rules := DefaultRules()
+	if path == "" {
+		return rules, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, fmt.Errorf("read referrer rules: %w", err)
+	}
+	var extra Rules
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return Rules{}, fmt.Errorf("parse referrer rules: %w", err)
+	}
+
+	rules.Search = append(rules.Search, extra.Search...)
+	rules.Social = append(rules.Social, extra.Social...)
+	rules.Email = append(rules.Email, extra.Email...)
+	return rules, nil
+}
+
+// UTM holds the campaign parameters of a landing page URL
+type UTM struct {
+	Source   string
+	Medium   string
+	Campaign string
+}
+
+// Source is where a visit came from
+type Source struct {
+	// Host is the normalized referring host, empty for direct visits
+	Host    string
+	Channel string
+}
+
+// Classifier assigns channels to referrers
+type Classifier struct {
+	// Email is checked first so mail.google.com is not a Google search
+	channels []channelRules
+	internal []string
+}
+
+type channelRules struct {
+	channel string
+	rules   []string
+}
+
+// NewClassifier creates a Classifier; referrers from internalHosts, our own
+// site, are treated as direct visits
+func NewClassifier(rules Rules, internalHosts ...string) *Classifier {
+	c := &Classifier{
+		channels: []channelRules{
+			{Email, normalizeRules(rules.Email)},
+			{Social, normalizeRules(rules.Social)},
+			{Search, normalizeRules(rules.Search)},
+		},
+	}
+	for _, host := range internalHosts {
+		if host = NormalizeHost(host); host != "" {
+			c.internal = append(c.internal, host)
+		}
+	}
+	return c
+}
+
+// Classify returns the source of a visit from its referrer URL and UTM
+// parameters. A utm_medium naming a channel wins; otherwise the referring
+// host, or failing that utm_source, is looked up in the rules.
+func (c *Classifier) Classify(referrer string, utm UTM) Source {
+	source := Source{Host: HostOf(referrer)}
+	for _, host := range c.internal {
+		if source.Host == host {
+			source.Host = ""
+		}
+	}
+
+	switch medium := strings.ToLower(utm.Medium); medium {
+	case Search, Social, Email:
+		source.Channel = medium
+		return source
+	case "organic":
+		source.Channel = Search
+		return source
+	}
+
+	if source.Host != "" {
+		source.Channel = c.lookup(source.Host, matchHost)
+		return source
+	}
+	if utm.Source != "" {
+		source.Channel = c.lookup(strings.ToLower(utm.Source), matchName)
+		return source
+	}
+	source.Channel = Direct
+	return source
+}
+
+func (c *Classifier) lookup(value string, match func(value, rule string) bool) string {
+	for _, channel := range c.channels {
+		for _, rule := range channel.rules {
+			if match(value, rule) {
+				return channel.channel
+			}
+		}
+	}
+	return Other
+}
+
+// matchHost reports whether host is the rule's domain or a subdomain of it
+func matchHost(host, rule string) bool {
+	if base, ok := strings.CutSuffix(rule, ".*"); ok {
+		return strings.HasPrefix(host, base+".") || strings.Contains(host, "."+base+".")
+	}
+	return host == rule || strings.HasSuffix(host, "."+rule)
+}
+
+// matchName reports whether a utm_source such as "linkedin" names the rule's site
+func matchName(source, rule string) bool {
+	if source == rule || matchHost(source, rule) {
+		return true
+	}
+	name, _, _ := strings.Cut(strings.TrimSuffix(rule, ".*"), ".")
+	return source == name
+}
+
+// HostOf returns the normalized host of a referrer URL, or "" when it has none
+func HostOf(referrer string) string {
+	referrer = strings.TrimSpace(referrer)
+	if referrer == "" {
+		return ""
+	}
+	if !strings.Contains(referrer, "://") {
+		referrer = "https://" + referrer
+	}
+	u, err := url.Parse(referrer)
+	if err != nil {
+		return ""
+	}
+	return NormalizeHost(u.Host)
+}
+
+// NormalizeHost lowercases a host and drops its port and leading "www." or "m."
+func NormalizeHost(host string) string {
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h
+	}
+	host = strings.TrimSuffix(host, ".")
+	for _, prefix := range []string{"www.", "m."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	return host
+}
+
+func normalizeRules(rules []string) []string {
+	normalized := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if rule = strings.ToLower(strings.TrimSpace(rule)); rule != "" {
+			normalized = append(normalized, rule)
+		}
+	}
+	return normalized
+}
+
+// ParseUTM removes the UTM parameters from a page URL such as
+// "/?utm_source=linkedin" or "/#/projects?utm_source=linkedin", returning the
+// remaining page and the parameters. Other query parameters are kept.
+func ParseUTM(page string) (string, UTM) {
+	if !strings.Contains(page, "utm_") {
+		return page, UTM{}
+	}
+	u, err := url.Parse(page)
+	if err != nil {
+		return page, UTM{}
+	}
+
+	var utm UTM
+	u.RawQuery = stripUTM(u.Query(), &utm)
+
+	// Hash-routed apps carry their query inside the fragment
+	if route, rawQuery, ok := strings.Cut(u.Fragment, "?"); ok {
+		query, _ := url.ParseQuery(rawQuery)
+		u.Fragment = route
+		if rest := stripUTM(query, &utm); rest != "" {
+			u.Fragment += "?" + rest
+		}
+	}
+
+	if u.Path == "" && u.Host == "" {
+		u.Path = "/"
+	}
+	return u.String(), utm
+}
+
+// stripUTM moves the utm_ parameters of query into utm, keeping values already
+// set, and returns the encoded remaining query
+func stripUTM(query url.Values, utm *UTM) string {
+	fields := map[string]*string{
+		"utm_source":   &utm.Source,
+		"utm_medium":   &utm.Medium,
+		"utm_campaign": &utm.Campaign,
+	}
+	for key := range query {
+		if !strings.HasPrefix(key, "utm_") {
+			continue
+		}
+		if field, ok := fields[key]; ok && *field == "" {
+			*field = strings.TrimSpace(query.Get(key))
+		}
+		query.Del(key)
+	}
+	return query.Encode()
+}
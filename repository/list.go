@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"portfolio-api/listing"
+	"portfolio-api/models"
+)
+
+// Lists that grow without bound are sorted and paged by the repository. Their
+// sortable fields live here so the SQL columns stay next to the Go values.
+
+// UserList describes how the users list sorts
+var UserList = listing.Resource[models.User]{
+	Sort: map[string]func(models.User) interface{}{
+		"name":       func(u models.User) interface{} { return strings.ToLower(u.Name) },
+		"email":      func(u models.User) interface{} { return strings.ToLower(u.Email) },
+		"role":       func(u models.User) interface{} { return u.Role },
+		"created_at": func(u models.User) interface{} { return u.CreatedAt },
+		"updated_at": func(u models.User) interface{} { return u.UpdatedAt },
+	},
+	DefaultSort: []listing.SortKey{{Field: "created_at", Desc: true}},
+	ID:          func(u models.User) string { return u.ID },
+}
+
+// userSortColumns are the SQL counterparts of the UserList sort values
+var userSortColumns = map[string]sortColumn{
+	"name":       {expr: "LOWER(name)", kind: sortText},
+	"email":      {expr: "LOWER(email)", kind: sortText},
+	"role":       {expr: "role", kind: sortText},
+	"created_at": {expr: "created_at", kind: sortTime},
+	"updated_at": {expr: "updated_at", kind: sortTime},
+}
+
+// ContactList describes how the contact inbox sorts
+var ContactList = listing.Resource[models.ContactMessage]{
+	Sort: map[string]func(models.ContactMessage) interface{}{
+		"name":       func(m models.ContactMessage) interface{} { return strings.ToLower(m.Name) },
+		"email":      func(m models.ContactMessage) interface{} { return strings.ToLower(m.Email) },
+		"subject":    func(m models.ContactMessage) interface{} { return strings.ToLower(m.Subject) },
+		"status":     func(m models.ContactMessage) interface{} { return m.Status },
+		"spam_score": func(m models.ContactMessage) interface{} { return m.SpamScore },
+		"created_at": func(m models.ContactMessage) interface{} { return m.CreatedAt },
+		"read_at":    func(m models.ContactMessage) interface{} { return m.ReadAt },
+	},
+	DefaultSort: []listing.SortKey{{Field: "created_at", Desc: true}},
+	ID:          func(m models.ContactMessage) string { return m.ID },
+}
+
+// contactSortColumns are the SQL counterparts of the ContactList sort values
+var contactSortColumns = map[string]sortColumn{
+	"name":       {expr: "LOWER(name)", kind: sortText},
+	"email":      {expr: "LOWER(email)", kind: sortText},
+	"subject":    {expr: "LOWER(subject)", kind: sortText},
+	"status":     {expr: "COALESCE(status, 'unread')", kind: sortText},
+	"spam_score": {expr: "spam_score", kind: sortInteger},
+	"created_at": {expr: "created_at", kind: sortTime},
+	"read_at":    {expr: "read_at", kind: sortTime},
+}
+
+// ProjectList describes how project lists sort
+var ProjectList = listing.Resource[models.Project]{
+	Sort: map[string]func(models.Project) interface{}{
+		"title":    func(p models.Project) interface{} { return strings.ToLower(p.Title) },
+		"status":   func(p models.Project) interface{} { return p.Status },
+		"featured": func(p models.Project) interface{} { return p.Featured },
+		// A project without a start date has the zero time, stored as NULL
+		"start_date": func(p models.Project) interface{} {
+			if p.StartDate.IsZero() {
+				return (*time.Time)(nil)
+			}
+			return p.StartDate
+		},
+		"end_date":     func(p models.Project) interface{} { return p.EndDate },
+		"created_at":   func(p models.Project) interface{} { return p.CreatedAt },
+		"updated_at":   func(p models.Project) interface{} { return p.UpdatedAt },
+		"publish_at":   func(p models.Project) interface{} { return p.PublishAt },
+		"published_at": func(p models.Project) interface{} { return p.PublishedAt },
+	},
+	DefaultSort: []listing.SortKey{{Field: "created_at", Desc: true}},
+	ID:          func(p models.Project) string { return p.ID },
+}
+
+// projectSortColumns are the SQL counterparts of the ProjectList sort values
+var projectSortColumns = map[string]sortColumn{
+	"title":        {expr: "LOWER(title)", kind: sortText},
+	"status":       {expr: "status", kind: sortText},
+	"featured":     {expr: "COALESCE(featured, false)", kind: sortBool},
+	"start_date":   {expr: "start_date", kind: sortTime},
+	"end_date":     {expr: "end_date", kind: sortTime},
+	"created_at":   {expr: "created_at", kind: sortTime},
+	"updated_at":   {expr: "updated_at", kind: sortTime},
+	"publish_at":   {expr: "publish_at", kind: sortTime},
+	"published_at": {expr: "published_at", kind: sortTime},
+}
+
+// SkillList describes how skill lists sort
+var SkillList = listing.Resource[models.Skill]{
+	Sort: map[string]func(models.Skill) interface{}{
+		"name":      func(s models.Skill) interface{} { return strings.ToLower(s.Name) },
+		"category":  func(s models.Skill) interface{} { return s.Category },
+		"level":     func(s models.Skill) interface{} { return s.Level },
+		"years_exp": func(s models.Skill) interface{} { return s.YearsExp },
+		"featured":  func(s models.Skill) interface{} { return s.Featured },
+	},
+	DefaultSort: []listing.SortKey{{Field: "featured", Desc: true}, {Field: "years_exp", Desc: true}, {Field: "name"}},
+	ID:          func(s models.Skill) string { return s.ID },
+}
+
+// skillSortColumns are the SQL counterparts of the SkillList sort values
+var skillSortColumns = map[string]sortColumn{
+	"name":      {expr: "LOWER(name)", kind: sortText},
+	"category":  {expr: "category", kind: sortText},
+	"level":     {expr: "level", kind: sortText},
+	"years_exp": {expr: "COALESCE(years_exp, 0)", kind: sortInteger},
+	"featured":  {expr: "COALESCE(featured, false)", kind: sortBool},
+}
+
+// SearchList describes how search results sort. Their IDs are the result
+// type and the ID of the matching row, as in "project/<uuid>".
+var SearchList = listing.Resource[models.SearchResult]{
+	Sort: map[string]func(models.SearchResult) interface{}{
+		"rank":  func(r models.SearchResult) interface{} { return r.Rank },
+		"type":  func(r models.SearchResult) interface{} { return r.Type },
+		"title": func(r models.SearchResult) interface{} { return strings.ToLower(r.Title) },
+	},
+	DefaultSort: []listing.SortKey{{Field: "rank", Desc: true}, {Field: "title"}},
+	ID:          func(r models.SearchResult) string { return r.Type + "/" + r.ID },
+}
+
+// searchSortColumns are the SQL counterparts of the SearchList sort values
+var searchSortColumns = map[string]sortColumn{
+	"rank":  {expr: "rank", kind: sortFloat},
+	"type":  {expr: "type", kind: sortText},
+	"title": {expr: "LOWER(title)", kind: sortText},
+}
+
+// validSearchKey reports whether key is the ID of a search result
+func validSearchKey(key string) bool {
+	docType, id, ok := strings.Cut(key, "/")
+	return ok && slices.Contains(models.SearchTypes, docType) && validID(id)
+}
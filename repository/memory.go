@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"portfolio-api/search"
+)
+
+// NewMemory returns empty in-memory repositories, mainly for tests and local runs
+func NewMemory() *Repositories {
+	index := search.NewIndex()
+	users := newMemoryUserRepository(index)
+	return &Repositories{
+		Users:    users,
+		Projects: newMemoryProjectRepository(index),
+		Skills:   newMemorySkillRepository(index),
+		Contacts: newMemoryContactRepository(),
+		Visits:   newMemoryVisitRepository(),
+		Rollups:  newMemoryRollupRepository(),
+		Clicks:   newMemoryClickRepository(),
+		Search:   &memorySearchRepository{index: index, users: users},
+		Salts:    newMemoryVisitorSaltRepository(),
+		APIKeys:  newMemoryAPIKeyRepository(),
+	}
+}
+
+// newID generates identifiers compatible with the UUID primary keys used in Postgres
+func newID() string {
+	return uuid.NewString()
+}
+
+// cloneStrings copies a slice so callers cannot mutate stored records
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return append([]string{}, values...)
+}
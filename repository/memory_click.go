@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"portfolio-api/models"
+)
+
+type memoryClickRepository struct {
+	mu     sync.RWMutex
+	clicks []models.ProjectClick
+}
+
+func newMemoryClickRepository() *memoryClickRepository {
+	return &memoryClickRepository{}
+}
+
+func (r *memoryClickRepository) Create(ctx context.Context, click *models.ProjectClick) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	click.ID = newID()
+	if click.CreatedAt.IsZero() {
+		click.CreatedAt = time.Now()
+	}
+	r.clicks = append(r.clicks, *click)
+	return nil
+}
+
+func (r *memoryClickRepository) Count(ctx context.Context, filter ClickFilter) ([]ClickCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type key struct{ projectID, target string }
+	totals := make(map[key]int)
+	for _, click := range r.clicks {
+		if len(filter.ProjectIDs) > 0 && !slices.Contains(filter.ProjectIDs, click.ProjectID) {
+			continue
+		}
+		if filter.IsBot != nil && click.IsBot != *filter.IsBot {
+			continue
+		}
+		totals[key{click.ProjectID, click.Target}]++
+	}
+
+	counts := make([]ClickCount, 0, len(totals))
+	for k, n := range totals {
+		counts = append(counts, ClickCount{ProjectID: k.projectID, Target: k.target, Clicks: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].ProjectID != counts[j].ProjectID {
+			return counts[i].ProjectID < counts[j].ProjectID
+		}
+		return counts[i].Target < counts[j].Target
+	})
+	return counts, nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"portfolio-api/listing"
+	"portfolio-api/models"
+)
+
+type memoryContactRepository struct {
+	mu       sync.RWMutex
+	messages map[string]models.ContactMessage
+}
+
+func newMemoryContactRepository() *memoryContactRepository {
+	return &memoryContactRepository{messages: make(map[string]models.ContactMessage)}
+}
+
+// matchesContactFilter mirrors the SQL filtering in sqlContactRepository.List
+func matchesContactFilter(message models.ContactMessage, filter ContactFilter) bool {
+	if len(filter.Statuses) > 0 {
+		found := false
+		for _, status := range filter.Statuses {
+			if message.Status == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !filter.From.IsZero() && message.CreatedAt.Before(filter.From) {
+		return false
+	}
+	if !filter.To.IsZero() && !message.CreatedAt.Before(filter.To) {
+		return false
+	}
+	if filter.Search != "" {
+		term := strings.ToLower(filter.Search)
+		for _, field := range []string{message.Name, message.Email, message.Subject, message.Message} {
+			if strings.Contains(strings.ToLower(field), term) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (r *memoryContactRepository) Create(ctx context.Context, message *models.ContactMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	message.ID = newID()
+	message.CreatedAt = time.Now()
+	message.SpamReasons = cloneStrings(message.SpamReasons)
+	r.messages[message.ID] = *message
+	return nil
+}
+
+func (r *memoryContactRepository) ListPage(ctx context.Context, filter ContactFilter, query listing.Query) (listing.Page[models.ContactMessage], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	messages := []models.ContactMessage{}
+	for _, message := range r.messages {
+		if matchesContactFilter(message, filter) {
+			messages = append(messages, message)
+		}
+	}
+
+	return listing.Paginate(messages, query, ContactList), nil
+}
+
+func (r *memoryContactRepository) Get(ctx context.Context, id string) (*models.ContactMessage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	message, ok := r.messages[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &message, nil
+}
+
+func (r *memoryContactRepository) UpdateStatus(ctx context.Context, message *models.ContactMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.messages[message.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	stored.Status = message.Status
+	stored.ReadAt = message.ReadAt
+	stored.RepliedAt = message.RepliedAt
+	r.messages[message.ID] = stored
+	return nil
+}
+
+func (r *memoryContactRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.messages[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.messages, id)
+	return nil
+}
@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"portfolio-api/listing"
+	"portfolio-api/models"
+	"portfolio-api/search"
+	"portfolio-api/slug"
+)
+
+type memoryProjectRepository struct {
+	mu       sync.RWMutex
+	projects map[string]models.Project
+	// retired maps slugs projects used before to the project IDs
+	retired map[string]string
+	index   *search.Index
+}
+
+func newMemoryProjectRepository(index *search.Index) *memoryProjectRepository {
+	return &memoryProjectRepository{
+		projects: make(map[string]models.Project),
+		retired:  make(map[string]string),
+		index:    index,
+	}
+}
+
+func cloneProject(project models.Project) models.Project {
+	project.TechStack = cloneStrings(project.TechStack)
+	project.EndDate = cloneTime(project.EndDate)
+	project.PublishAt = cloneTime(project.PublishAt)
+	project.PublishedAt = cloneTime(project.PublishedAt)
+	return project
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	return &clone
+}
+
+// reindex keeps a project in the search index while it is published
+func (r *memoryProjectRepository) reindex(project models.Project) {
+	if project.Publication == models.PublicationPublished {
+		r.index.Put(projectDocument(project))
+	} else {
+		r.index.Remove(models.SearchTypeProject, project.ID)
+	}
+}
+
+// matchesProjectFilter mirrors the SQL filtering in sqlProjectRepository.where
+func matchesProjectFilter(project models.Project, filter ProjectFilter) bool {
+	if filter.UserID != "" && project.UserID != filter.UserID {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, project.Status) {
+		return false
+	}
+	if filter.Featured != nil && project.Featured != *filter.Featured {
+		return false
+	}
+	if len(filter.TechStack) > 0 {
+		matched := 0
+		for _, tech := range filter.TechStack {
+			if slices.Contains(project.TechStack, tech) {
+				matched++
+			}
+		}
+		if matched == 0 || (filter.AllTech && matched < len(filter.TechStack)) {
+			return false
+		}
+	}
+	if !filter.StartedAfter.IsZero() && (project.StartDate.IsZero() || project.StartDate.Before(filter.StartedAfter)) {
+		return false
+	}
+	if !filter.EndedBefore.IsZero() && (project.EndDate == nil || !project.EndDate.Before(filter.EndedBefore)) {
+		return false
+	}
+	if filter.HasLiveURL != nil && (project.LiveURL != "") != *filter.HasLiveURL {
+		return false
+	}
+	if len(filter.Publications) > 0 && !slices.Contains(filter.Publications, project.Publication) {
+		return false
+	}
+	if filter.Visible && project.Publication != models.PublicationPublished &&
+		(filter.ViewerID == "" || project.UserID != filter.ViewerID) {
+		return false
+	}
+	if filter.Search != "" {
+		term := strings.ToLower(filter.Search)
+		return strings.Contains(strings.ToLower(project.Title), term) ||
+			strings.Contains(strings.ToLower(project.Description), term)
+	}
+	return true
+}
+
+func (r *memoryProjectRepository) List(ctx context.Context, filter ProjectFilter) ([]models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	projects := []models.Project{}
+	for _, project := range r.projects {
+		if matchesProjectFilter(project, filter) {
+			projects = append(projects, cloneProject(project))
+		}
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].CreatedAt.After(projects[j].CreatedAt)
+	})
+
+	return projects, nil
+}
+
+func (r *memoryProjectRepository) ListPage(ctx context.Context, filter ProjectFilter, query listing.Query) (listing.Page[models.Project], error) {
+	projects, err := r.List(ctx, filter)
+	if err != nil {
+		return listing.Page[models.Project]{}, err
+	}
+	return listing.Paginate(projects, query, ProjectList), nil
+}
+
+func (r *memoryProjectRepository) Get(ctx context.Context, id string) (*models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	project, ok := r.projects[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	project = cloneProject(project)
+	return &project, nil
+}
+
+func (r *memoryProjectRepository) GetBySlug(ctx context.Context, s string) (*models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, project := range r.projects {
+		if project.Slug == s {
+			project = cloneProject(project)
+			return &project, nil
+		}
+	}
+	project, ok := r.projects[r.retired[s]]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	project = cloneProject(project)
+	return &project, nil
+}
+
+// claimSlug mirrors the SQL claimSlug; callers hold the write lock
+func (r *memoryProjectRepository) claimSlug(project *models.Project) error {
+	if project.Slug == "" {
+		taken := make(map[string]bool)
+		for id, other := range r.projects {
+			if id != project.ID {
+				taken[other.Slug] = true
+			}
+		}
+		for s, id := range r.retired {
+			if id != project.ID {
+				taken[s] = true
+			}
+		}
+		project.Slug = slug.Unique(slug.Make(project.Title), taken)
+	}
+
+	for id, other := range r.projects {
+		if id != project.ID && other.Slug == project.Slug {
+			return ErrConflict
+		}
+	}
+	delete(r.retired, project.Slug)
+	return nil
+}
+
+func (r *memoryProjectRepository) Create(ctx context.Context, project *models.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	project.ID = newID()
+	if err := r.claimSlug(project); err != nil {
+		return err
+	}
+	project.TechStack = cloneStrings(project.TechStack)
+	project.CreatedAt = now
+	project.UpdatedAt = now
+
+	r.projects[project.ID] = cloneProject(*project)
+	r.reindex(*project)
+	return nil
+}
+
+func (r *memoryProjectRepository) Update(ctx context.Context, project *models.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.projects[project.ID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if project.Slug == "" || project.Slug != existing.Slug {
+		if err := r.claimSlug(project); err != nil {
+			return err
+		}
+		if existing.Slug != "" && existing.Slug != project.Slug {
+			r.retired[existing.Slug] = project.ID
+		}
+	}
+	project.CreatedAt = existing.CreatedAt
+	project.UpdatedAt = time.Now()
+
+	r.projects[project.ID] = cloneProject(*project)
+	r.reindex(*project)
+	return nil
+}
+
+func (r *memoryProjectRepository) PublishDue(ctx context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	published := 0
+	for id, project := range r.projects {
+		if project.Publication != models.PublicationScheduled || project.PublishAt == nil || project.PublishAt.After(now) {
+			continue
+		}
+		project.Publication = models.PublicationPublished
+		project.PublishedAt = project.PublishAt
+		project.PublishAt = nil
+		project.UpdatedAt = now
+		r.projects[id] = project
+		r.reindex(project)
+		published++
+	}
+	return published, nil
+}
+
+func (r *memoryProjectRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.projects[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.projects, id)
+	for s, projectID := range r.retired {
+		if projectID == id {
+			delete(r.retired, s)
+		}
+	}
+	r.index.Remove(models.SearchTypeProject, id)
+	return nil
+}
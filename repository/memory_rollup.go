@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"portfolio-api/models"
+)
+
+type memoryRollupRepository struct {
+	mu     sync.RWMutex
+	hourly []models.Rollup
+	daily  []models.Rollup
+}
+
+func newMemoryRollupRepository() *memoryRollupRepository {
+	return &memoryRollupRepository{}
+}
+
+func (r *memoryRollupRepository) ListHourly(ctx context.Context, filter RollupFilter) ([]models.Rollup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return filterRollups(r.hourly, filter), nil
+}
+
+func (r *memoryRollupRepository) ListDaily(ctx context.Context, filter RollupFilter) ([]models.Rollup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return filterRollups(r.daily, filter), nil
+}
+
+func (r *memoryRollupRepository) ReplaceDay(ctx context.Context, day time.Time, hourly, daily []models.Rollup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	start := day.UTC()
+	end := start.AddDate(0, 0, 1)
+	r.hourly = replaceRollups(r.hourly, start, end, hourly)
+	r.daily = replaceRollups(r.daily, start, end, daily)
+	return nil
+}
+
+func (r *memoryRollupRepository) Watermark(ctx context.Context) (time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var last time.Time
+	for _, rollup := range r.daily {
+		if rollup.Dimension == rollupTotal && rollup.PeriodStart.After(last) {
+			last = rollup.PeriodStart
+		}
+	}
+	if last.IsZero() {
+		return last, nil
+	}
+	return last.UTC().AddDate(0, 0, 1), nil
+}
+
+func filterRollups(rollups []models.Rollup, filter RollupFilter) []models.Rollup {
+	filtered := []models.Rollup{}
+	for _, rollup := range rollups {
+		if !filter.From.IsZero() && rollup.PeriodStart.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !rollup.PeriodStart.Before(filter.To) {
+			continue
+		}
+		if filter.IsBot != nil && rollup.IsBot != *filter.IsBot {
+			continue
+		}
+		if len(filter.Dimensions) > 0 && !slices.Contains(filter.Dimensions, rollup.Dimension) {
+			continue
+		}
+		filtered = append(filtered, rollup)
+	}
+	return filtered
+}
+
+// replaceRollups swaps the rollups within [start, end) for replacements,
+// keeping the slice ordered by period like the SQL listing
+func replaceRollups(rollups []models.Rollup, start, end time.Time, replacements []models.Rollup) []models.Rollup {
+	kept := make([]models.Rollup, 0, len(rollups)+len(replacements))
+	for _, rollup := range rollups {
+		if rollup.PeriodStart.Before(start) || !rollup.PeriodStart.Before(end) {
+			kept = append(kept, rollup)
+		}
+	}
+	kept = append(kept, replacements...)
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].PeriodStart.Before(kept[j].PeriodStart) })
+	return kept
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"sync"
+)
+
+type memoryVisitorSaltRepository struct {
+	mu    sync.Mutex
+	salts map[string]string
+}
+
+func newMemoryVisitorSaltRepository() *memoryVisitorSaltRepository {
+	return &memoryVisitorSaltRepository{salts: make(map[string]string)}
+}
+
+func (r *memoryVisitorSaltRepository) Salt(ctx context.Context, day, candidate string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if salt, ok := r.salts[day]; ok {
+		return salt, nil
+	}
+	r.salts[day] = candidate
+	return candidate, nil
+}
+
+func (r *memoryVisitorSaltRepository) DeleteBefore(ctx context.Context, day string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for d := range r.salts {
+		if d < day {
+			delete(r.salts, d)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"portfolio-api/listing"
+	"portfolio-api/models"
+	"portfolio-api/search"
+)
+
+// memorySearchRepository searches the inverted index the memory user,
+// project and skill repositories keep up to date
+type memorySearchRepository struct {
+	index *search.Index
+	users *memoryUserRepository
+}
+
+func (r *memorySearchRepository) SearchPage(ctx context.Context, query SearchQuery, list listing.Query) (listing.Page[models.SearchResult], error) {
+	private := false
+	users, err := r.users.List(ctx, UserFilter{IsPublic: &private})
+	if err != nil {
+		return listing.Page[models.SearchResult]{}, err
+	}
+	hidden := hiddenOwners(users, query)
+
+	hits := []search.Hit{}
+	for _, hit := range r.index.Search(query.Terms, query.Types) {
+		if !hidden[hit.Owner] {
+			hits = append(hits, hit)
+		}
+	}
+	return listing.Paginate(searchResults(hits, query.Terms), list, SearchList), nil
+}
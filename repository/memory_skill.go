@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"portfolio-api/listing"
+	"portfolio-api/models"
+	"portfolio-api/search"
+)
+
+type memorySkillRepository struct {
+	mu     sync.RWMutex
+	skills map[string]models.Skill
+	index  *search.Index
+}
+
+func newMemorySkillRepository(index *search.Index) *memorySkillRepository {
+	return &memorySkillRepository{skills: make(map[string]models.Skill), index: index}
+}
+
+func (r *memorySkillRepository) List(ctx context.Context, filter SkillFilter) ([]models.Skill, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	skills := []models.Skill{}
+	for _, skill := range r.skills {
+		if filter.UserID != "" && skill.UserID != filter.UserID {
+			continue
+		}
+		if filter.Category != "" && skill.Category != filter.Category {
+			continue
+		}
+		if filter.Featured != nil && skill.Featured != *filter.Featured {
+			continue
+		}
+		skills = append(skills, skill)
+	}
+
+	// Same ordering as the Postgres implementation
+	sort.Slice(skills, func(i, j int) bool {
+		if skills[i].Featured != skills[j].Featured {
+			return skills[i].Featured
+		}
+		if skills[i].YearsExp != skills[j].YearsExp {
+			return skills[i].YearsExp > skills[j].YearsExp
+		}
+		return skills[i].Name < skills[j].Name
+	})
+
+	return skills, nil
+}
+
+func (r *memorySkillRepository) ListPage(ctx context.Context, filter SkillFilter, query listing.Query) (listing.Page[models.Skill], error) {
+	skills, err := r.List(ctx, filter)
+	if err != nil {
+		return listing.Page[models.Skill]{}, err
+	}
+	return listing.Paginate(skills, query, SkillList), nil
+}
+
+func (r *memorySkillRepository) Get(ctx context.Context, id string) (*models.Skill, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	skill, ok := r.skills[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &skill, nil
+}
+
+func (r *memorySkillRepository) Create(ctx context.Context, skill *models.Skill) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	skill.ID = newID()
+	r.skills[skill.ID] = *skill
+	r.index.Put(skillDocument(*skill))
+	return nil
+}
+
+func (r *memorySkillRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.skills[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.skills, id)
+	r.index.Remove(models.SearchTypeSkill, id)
+	return nil
+}
@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"portfolio-api/listing"
+	"portfolio-api/models"
+)
+
+func TestMemoryNotFound(t *testing.T) {
+	missing := newID()
+	tests := []struct {
+		name string
+		op   func(ctx context.Context, repos *Repositories) error
+	}{
+		{"get user", func(ctx context.Context, repos *Repositories) error {
+			_, err := repos.Users.Get(ctx, missing)
+			return err
+		}},
+		{"update user", func(ctx context.Context, repos *Repositories) error {
+			return repos.Users.Update(ctx, &models.User{ID: missing, Email: "a@example.com"})
+		}},
+		{"delete user", func(ctx context.Context, repos *Repositories) error {
+			return repos.Users.Delete(ctx, missing)
+		}},
+		{"get project", func(ctx context.Context, repos *Repositories) error {
+			_, err := repos.Projects.Get(ctx, missing)
+			return err
+		}},
+		{"get project by slug", func(ctx context.Context, repos *Repositories) error {
+			_, err := repos.Projects.GetBySlug(ctx, "missing")
+			return err
+		}},
+		{"update project", func(ctx context.Context, repos *Repositories) error {
+			return repos.Projects.Update(ctx, &models.Project{ID: missing, Title: "Missing"})
+		}},
+		{"delete project", func(ctx context.Context, repos *Repositories) error {
+			return repos.Projects.Delete(ctx, missing)
+		}},
+		{"get skill", func(ctx context.Context, repos *Repositories) error {
+			_, err := repos.Skills.Get(ctx, missing)
+			return err
+		}},
+		{"delete skill", func(ctx context.Context, repos *Repositories) error {
+			return repos.Skills.Delete(ctx, missing)
+		}},
+		{"get contact message", func(ctx context.Context, repos *Repositories) error {
+			_, err := repos.Contacts.Get(ctx, missing)
+			return err
+		}},
+		{"update contact status", func(ctx context.Context, repos *Repositories) error {
+			return repos.Contacts.UpdateStatus(ctx, &models.ContactMessage{ID: missing, Status: models.ContactStatusRead})
+		}},
+		{"delete contact message", func(ctx context.Context, repos *Repositories) error {
+			return repos.Contacts.Delete(ctx, missing)
+		}},
+		{"get API key by hash", func(ctx context.Context, repos *Repositories) error {
+			_, err := repos.APIKeys.GetByHash(ctx, "missing")
+			return err
+		}},
+		{"mark API key used", func(ctx context.Context, repos *Repositories) error {
+			return repos.APIKeys.MarkUsed(ctx, missing, time.Now())
+		}},
+		{"revoke API key", func(ctx context.Context, repos *Repositories) error {
+			return repos.APIKeys.Revoke(ctx, missing)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.op(context.Background(), NewMemory()); !errors.Is(err, ErrNotFound) {
+				t.Errorf("got %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestMemoryUserCRUD(t *testing.T) {
+	ctx := context.Background()
+	users := NewMemory().Users
+
+	user := models.User{Name: "Jane", Email: "jane@example.com", Skills: []string{"Go"}}
+	if err := users.Create(ctx, &user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if user.ID == "" || user.CreatedAt.IsZero() {
+		t.Fatalf("Create did not set ID and CreatedAt: %+v", user)
+	}
+	duplicate := models.User{Name: "Jane again", Email: "jane@example.com"}
+	if err := users.Create(ctx, &duplicate); !errors.Is(err, ErrConflict) {
+		t.Errorf("Create with a taken email: got %v, want ErrConflict", err)
+	}
+
+	// Stored records must not share memory with the caller
+	user.Skills[0] = "Rust"
+	got, err := users.Get(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Skills[0] != "Go" {
+		t.Errorf("stored skills changed through the caller's slice: %v", got.Skills)
+	}
+
+	got.Name = "Jane Doe"
+	if err := users.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got, _ := users.Get(ctx, user.ID); got.Name != "Jane Doe" {
+		t.Errorf("Update did not persist the name: %q", got.Name)
+	}
+
+	if err := users.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := users.Get(ctx, user.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryProjectCRUD(t *testing.T) {
+	ctx := context.Background()
+	projects := NewMemory().Projects
+
+	project := models.Project{
+		Title:       "Portfolio Website",
+		Description: "A portfolio",
+		TechStack:   []string{"Go"},
+		Status:      "completed",
+		Publication: models.PublicationPublished,
+		// Like the SQL repositories, Create records when it stored the project
+		CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := projects.Create(ctx, &project); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if project.ID == "" || project.Slug != "portfolio-website" {
+		t.Fatalf("Create did not set ID and slug: %+v", project)
+	}
+	if time.Since(project.CreatedAt) > time.Minute {
+		t.Errorf("Create kept the caller's CreatedAt %v", project.CreatedAt)
+	}
+
+	got, err := projects.GetBySlug(ctx, "portfolio-website")
+	if err != nil || got.ID != project.ID {
+		t.Fatalf("GetBySlug: %v, %+v", err, got)
+	}
+
+	got.Slug = "new-home"
+	if err := projects.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if retired, err := projects.GetBySlug(ctx, "portfolio-website"); err != nil || retired.Slug != "new-home" {
+		t.Errorf("retired slug: got %v, %+v", err, retired)
+	}
+
+	list, err := projects.List(ctx, ProjectFilter{TechStack: []string{"Go"}})
+	if err != nil || len(list) != 1 {
+		t.Fatalf("List: %v, %d projects", err, len(list))
+	}
+
+	if err := projects.Delete(ctx, project.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := projects.GetBySlug(ctx, "portfolio-website"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("retired slug after Delete: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemorySkillCRUD(t *testing.T) {
+	ctx := context.Background()
+	skills := NewMemory().Skills
+
+	skill := models.Skill{Name: "Go", Category: "backend", Level: "expert"}
+	if err := skills.Create(ctx, &skill); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got, err := skills.Get(ctx, skill.ID); err != nil || got.Name != "Go" {
+		t.Fatalf("Get: %v, %+v", err, got)
+	}
+	if list, err := skills.List(ctx, SkillFilter{Category: "frontend"}); err != nil || len(list) != 0 {
+		t.Errorf("List by another category: %v, %d skills", err, len(list))
+	}
+	if err := skills.Delete(ctx, skill.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := skills.Get(ctx, skill.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryContactCRUD(t *testing.T) {
+	ctx := context.Background()
+	contacts := NewMemory().Contacts
+
+	message := models.ContactMessage{
+		Name:    "Jane",
+		Email:   "jane@example.com",
+		Subject: "Hi",
+		Message: "Hello",
+		Status:  models.ContactStatusUnread,
+	}
+	if err := contacts.Create(ctx, &message); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if message.ID == "" || message.CreatedAt.IsZero() {
+		t.Fatalf("Create did not set ID and CreatedAt: %+v", message)
+	}
+
+	message.Status = models.ContactStatusRead
+	if err := contacts.UpdateStatus(ctx, &message); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if got, err := contacts.Get(ctx, message.ID); err != nil || got.Status != models.ContactStatusRead {
+		t.Fatalf("Get: %v, %+v", err, got)
+	}
+
+	if err := contacts.Delete(ctx, message.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := contacts.Get(ctx, message.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryAPIKeyCRUD(t *testing.T) {
+	ctx := context.Background()
+	keys := NewMemory().APIKeys
+
+	key := models.APIKey{Name: "ci", Prefix: "pk_test", KeyHash: "hash"}
+	if err := keys.Create(ctx, &key); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := keys.Create(ctx, &models.APIKey{Name: "copy", KeyHash: "hash"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("Create with a taken hash: got %v, want ErrConflict", err)
+	}
+
+	if err := keys.Revoke(ctx, key.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	got, err := keys.GetByHash(ctx, "hash")
+	if err != nil {
+		t.Fatalf("GetByHash: %v", err)
+	}
+	if got.RevokedAt == nil {
+		t.Errorf("Revoke did not set RevokedAt: %+v", got)
+	}
+}
+
+func TestPublishDue(t *testing.T) {
+	backends := map[string]func(t *testing.T) *Repositories{
+		"memory": func(t *testing.T) *Repositories { return NewMemory() },
+		"sqlite": newSQLiteRepositories,
+	}
+	now := time.Date(2030, 6, 1, 12, 0, 0, 0, time.UTC)
+	due := now.Add(-time.Minute)
+	exact := now
+	future := now.Add(time.Minute)
+
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			projects := open(t).Projects
+
+			tests := []struct {
+				title           string
+				publication     string
+				publishAt       *time.Time
+				wantPublication string
+			}{
+				{"Overdue", models.PublicationScheduled, &due, models.PublicationPublished},
+				{"Due now", models.PublicationScheduled, &exact, models.PublicationPublished},
+				{"Not yet", models.PublicationScheduled, &future, models.PublicationScheduled},
+				{"Draft", models.PublicationDraft, nil, models.PublicationDraft},
+			}
+			ids := make([]string, len(tests))
+			for i, tt := range tests {
+				project := models.Project{Title: tt.title, Description: "d", Status: "completed", Publication: tt.publication, PublishAt: tt.publishAt}
+				if err := projects.Create(ctx, &project); err != nil {
+					t.Fatalf("Create %s: %v", tt.title, err)
+				}
+				ids[i] = project.ID
+			}
+
+			published, err := projects.PublishDue(ctx, now)
+			if err != nil || published != 2 {
+				t.Fatalf("PublishDue = %d, %v; want 2", published, err)
+			}
+			if published, _ := projects.PublishDue(ctx, now); published != 0 {
+				t.Errorf("second PublishDue published %d projects, want 0", published)
+			}
+
+			for i, tt := range tests {
+				got, err := projects.Get(ctx, ids[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Publication != tt.wantPublication {
+					t.Errorf("%s: publication = %q, want %q", tt.title, got.Publication, tt.wantPublication)
+				}
+				if tt.wantPublication == models.PublicationPublished {
+					// The publication time is the scheduled one, not when the job ran
+					if got.PublishAt != nil || got.PublishedAt == nil || !got.PublishedAt.Equal(*tt.publishAt) {
+						t.Errorf("%s: publish_at %v, published_at %v; want nil and %v", tt.title, got.PublishAt, got.PublishedAt, *tt.publishAt)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSearchHidesPrivateUsers(t *testing.T) {
+	backends := map[string]func(t *testing.T) *Repositories{
+		"memory": func(t *testing.T) *Repositories { return NewMemory() },
+		"sqlite": newSQLiteRepositories,
+	}
+
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repos := open(t)
+
+			public := models.User{Name: "Kotlin Public", Email: "public@example.com", Role: models.RoleOwner, IsPublic: true}
+			private := models.User{Name: "Kotlin Private", Email: "private@example.com", Role: models.RoleOwner}
+			// hidden holds the IDs of the private user's profile, project and skill
+			hidden := make(map[string]bool)
+			for _, user := range []*models.User{&public, &private} {
+				if err := repos.Users.Create(ctx, user); err != nil {
+					t.Fatal(err)
+				}
+				project := models.Project{UserID: user.ID, Title: "Kotlin app", Description: "d", Status: "completed", Publication: models.PublicationPublished}
+				if err := repos.Projects.Create(ctx, &project); err != nil {
+					t.Fatal(err)
+				}
+				skill := models.Skill{UserID: user.ID, Name: "Kotlin", Category: "mobile", Level: "expert"}
+				if err := repos.Skills.Create(ctx, &skill); err != nil {
+					t.Fatal(err)
+				}
+				if !user.IsPublic {
+					hidden[user.ID], hidden[project.ID], hidden[skill.ID] = true, true, true
+				}
+			}
+
+			tests := []struct {
+				name  string
+				query SearchQuery
+				want  int
+			}{
+				{name: "anonymous", query: SearchQuery{Visible: true}, want: 3},
+				{name: "another user", query: SearchQuery{Visible: true, ViewerID: public.ID}, want: 3},
+				{name: "private user", query: SearchQuery{Visible: true, ViewerID: private.ID}, want: 6},
+				{name: "admin", query: SearchQuery{}, want: 6},
+			}
+			list, err := listing.Parse(url.Values{}, SearchList)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, tt := range tests {
+				tt.query.Terms = []string{"kotlin"}
+				page, err := repos.Search.SearchPage(ctx, tt.query, list)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if page.Total != tt.want {
+					t.Errorf("%s: %d results, want %d", tt.name, page.Total, tt.want)
+				}
+				for _, result := range page.Items {
+					if tt.want == 3 && hidden[result.ID] {
+						t.Errorf("%s: found the private user's %s", tt.name, result.Type)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"portfolio-api/listing"
+	"portfolio-api/models"
+	"portfolio-api/search"
+)
+
+type memoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[string]models.User
+	index *search.Index
+}
+
+func newMemoryUserRepository(index *search.Index) *memoryUserRepository {
+	return &memoryUserRepository{users: make(map[string]models.User), index: index}
+}
+
+// reindex keeps a user's profile searchable; searches drop private ones
+func (r *memoryUserRepository) reindex(user models.User) {
+	r.index.Put(userDocument(user))
+}
+
+func cloneUser(user models.User) models.User {
+	user.Skills = cloneStrings(user.Skills)
+	return user
+}
+
+// emailTaken reports whether another user already uses email; callers hold the lock
+func (r *memoryUserRepository) emailTaken(email, exceptID string) bool {
+	for id, user := range r.users {
+		if id != exceptID && strings.EqualFold(user.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryUserRepository) List(ctx context.Context, filter UserFilter) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range r.users {
+		if filter.IsPublic != nil && user.IsPublic != *filter.IsPublic {
+			continue
+		}
+		users = append(users, cloneUser(user))
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].CreatedAt.After(users[j].CreatedAt)
+	})
+
+	return users, nil
+}
+
+func (r *memoryUserRepository) ListPage(ctx context.Context, filter UserFilter, query listing.Query) (listing.Page[models.User], error) {
+	users, err := r.List(ctx, filter)
+	if err != nil {
+		return listing.Page[models.User]{}, err
+	}
+	return listing.Paginate(users, query, UserList), nil
+}
+
+func (r *memoryUserRepository) Get(ctx context.Context, id string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	user = cloneUser(user)
+	return &user, nil
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.emailTaken(user.Email, "") {
+		return ErrConflict
+	}
+
+	now := time.Now()
+	user.ID = newID()
+	user.Skills = cloneStrings(user.Skills)
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	r.users[user.ID] = cloneUser(*user)
+	r.reindex(*user)
+	return nil
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.users[user.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if r.emailTaken(user.Email, user.ID) {
+		return ErrConflict
+	}
+
+	user.Skills = cloneStrings(user.Skills)
+	user.CreatedAt = existing.CreatedAt
+	user.UpdatedAt = time.Now()
+
+	r.users[user.ID] = cloneUser(*user)
+	r.reindex(*user)
+	return nil
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.users, id)
+	r.index.Remove(models.SearchTypeUser, id)
+	return nil
+}
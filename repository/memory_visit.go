@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"portfolio-api/models"
+)
+
+type memoryVisitRepository struct {
+	mu     sync.RWMutex
+	visits []models.Visit
+}
+
+func newMemoryVisitRepository() *memoryVisitRepository {
+	return &memoryVisitRepository{}
+}
+
+func (r *memoryVisitRepository) Create(ctx context.Context, visit *models.Visit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	visit.ID = newID()
+	if visit.CreatedAt.IsZero() {
+		visit.CreatedAt = time.Now()
+	}
+	// Keep visits ordered by time so List matches the Postgres ordering
+	i := sort.Search(len(r.visits), func(i int) bool {
+		return r.visits[i].CreatedAt.After(visit.CreatedAt)
+	})
+	r.visits = append(r.visits, models.Visit{})
+	copy(r.visits[i+1:], r.visits[i:])
+	r.visits[i] = *visit
+	return nil
+}
+
+func (r *memoryVisitRepository) List(ctx context.Context, filter VisitFilter) ([]models.Visit, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	visits := []models.Visit{}
+	for _, visit := range r.visits {
+		if !filter.From.IsZero() && visit.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !visit.CreatedAt.Before(filter.To) {
+			continue
+		}
+		if filter.IsBot != nil && visit.IsBot != *filter.IsBot {
+			continue
+		}
+		visits = append(visits, visit)
+	}
+
+	return visits, nil
+}
+
+func (r *memoryVisitRepository) Get(ctx context.Context, id string) (*models.Visit, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, visit := range r.visits {
+		if visit.ID == id {
+			return &visit, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryVisitRepository) SetEndedAt(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.visits {
+		if r.visits[i].ID == id {
+			endedAt := at
+			r.visits[i].EndedAt = &endedAt
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryVisitRepository) Oldest(ctx context.Context) (time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.visits) == 0 {
+		return time.Time{}, nil
+	}
+	return r.visits[0].CreatedAt, nil
+}
+
+func (r *memoryVisitRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Visits are ordered by time, so the old ones form a prefix
+	i := sort.Search(len(r.visits), func(i int) bool {
+		return !r.visits[i].CreatedAt.Before(t)
+	})
+	r.visits = append([]models.Visit(nil), r.visits[i:]...)
+	return int64(i), nil
+}
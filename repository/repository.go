@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"portfolio-api/listing"
+	"portfolio-api/models"
+)
+
+var (
+	// ErrNotFound is returned when the requested record does not exist
+	ErrNotFound = errors.New("record not found")
+
+	// ErrConflict is returned when a write violates a uniqueness constraint
+	ErrConflict = errors.New("record already exists")
+)
+
+// UserFilter narrows the users returned by UserRepository.List
+type UserFilter struct {
+	IsPublic *bool
+}
+
+// ProjectFilter narrows the projects returned by ProjectRepository.List
+type ProjectFilter struct {
+	UserID   string
+	Statuses []string
+	Featured *bool
+	// TechStack matches projects using any of these technologies, or all
+	// of them with AllTech; names match as spelled in the tech stack
+	TechStack []string
+	AllTech   bool
+	// StartedAfter keeps projects started at or after it
+	StartedAfter time.Time
+	// EndedBefore keeps projects that ended before it
+	EndedBefore time.Time
+	HasLiveURL  *bool
+	// Search matches title or description, case-insensitively
+	Search string
+	// Publications keeps projects in these publication states
+	Publications []string
+	// Visible keeps only published projects, plus those of ViewerID when set
+	Visible  bool
+	ViewerID string
+}
+
+// SkillFilter narrows the skills returned by SkillRepository.List
+type SkillFilter struct {
+	UserID   string
+	Category string
+	Featured *bool
+}
+
+// ContactFilter narrows the messages returned by ContactRepository.ListPage
+type ContactFilter struct {
+	Statuses []string
+	From     time.Time
+	To       time.Time
+	// Search matches name, email, subject or message, case-insensitively
+	Search string
+}
+
+// VisitFilter narrows the visits returned by VisitRepository.List
+type VisitFilter struct {
+	From  time.Time
+	To    time.Time
+	IsBot *bool
+}
+
+// RollupFilter narrows the rollups returned by RollupRepository
+type RollupFilter struct {
+	From       time.Time
+	To         time.Time
+	IsBot      *bool
+	Dimensions []string
+}
+
+// ClickFilter narrows the clicks counted by ClickRepository.Count
+type ClickFilter struct {
+	ProjectIDs []string
+	IsBot      *bool
+}
+
+// ClickCount is the number of clicks on one target of a project
+type ClickCount struct {
+	ProjectID string
+	Target    string
+	Clicks    int
+}
+
+// SearchQuery describes a full-text search
+type SearchQuery struct {
+	// Terms are lowercased words; each must start a word of a match
+	Terms []string
+	// Types limits the result types; empty searches every type
+	Types []string
+	// Visible keeps only the profiles, projects and skills of public users,
+	// plus those of ViewerID when set
+	Visible  bool
+	ViewerID string
+}
+
+// UserRepository stores user profiles
+type UserRepository interface {
+	List(ctx context.Context, filter UserFilter) ([]models.User, error)
+	// ListPage returns the page of the users matching the filter that the
+	// query asks for, sorting and paging them in storage
+	ListPage(ctx context.Context, filter UserFilter, query listing.Query) (listing.Page[models.User], error)
+	Get(ctx context.Context, id string) (*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	Update(ctx context.Context, user *models.User) error
+	Delete(ctx context.Context, id string) error
+}
+
+// ProjectRepository stores portfolio projects. Saving a project without a
+// slug derives a unique one from its title; saving one with a slug another
+// project uses returns ErrConflict. Replaced slugs are kept so old links
+// still find the project, until another project claims them.
+type ProjectRepository interface {
+	List(ctx context.Context, filter ProjectFilter) ([]models.Project, error)
+	// ListPage returns the page of the projects matching the filter that the
+	// query asks for, sorting and paging them in storage
+	ListPage(ctx context.Context, filter ProjectFilter, query listing.Query) (listing.Page[models.Project], error)
+	Get(ctx context.Context, id string) (*models.Project, error)
+	// GetBySlug finds a project by its slug or, failing that, by a slug it
+	// used before; the returned project carries its current slug
+	GetBySlug(ctx context.Context, slug string) (*models.Project, error)
+	Create(ctx context.Context, project *models.Project) error
+	Update(ctx context.Context, project *models.Project) error
+	Delete(ctx context.Context, id string) error
+	// PublishDue publishes the scheduled projects whose publish time is not
+	// after now, returning how many it published
+	PublishDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// SkillRepository stores technical skills
+type SkillRepository interface {
+	List(ctx context.Context, filter SkillFilter) ([]models.Skill, error)
+	// ListPage returns the page of the skills matching the filter that the
+	// query asks for, sorting and paging them in storage
+	ListPage(ctx context.Context, filter SkillFilter, query listing.Query) (listing.Page[models.Skill], error)
+	Get(ctx context.Context, id string) (*models.Skill, error)
+	Create(ctx context.Context, skill *models.Skill) error
+	Delete(ctx context.Context, id string) error
+}
+
+// ContactRepository stores contact form submissions
+type ContactRepository interface {
+	Create(ctx context.Context, message *models.ContactMessage) error
+	// ListPage returns the page of the messages matching the filter that the
+	// query asks for, sorting and paging them in storage
+	ListPage(ctx context.Context, filter ContactFilter, query listing.Query) (listing.Page[models.ContactMessage], error)
+	Get(ctx context.Context, id string) (*models.ContactMessage, error)
+	UpdateStatus(ctx context.Context, message *models.ContactMessage) error
+	Delete(ctx context.Context, id string) error
+}
+
+// VisitRepository stores recorded page visits
+type VisitRepository interface {
+	Create(ctx context.Context, visit *models.Visit) error
+	Get(ctx context.Context, id string) (*models.Visit, error)
+	List(ctx context.Context, filter VisitFilter) ([]models.Visit, error)
+	// SetEndedAt records the last activity on a visit's page
+	SetEndedAt(ctx context.Context, id string, at time.Time) error
+	// Oldest returns the earliest visit time, or the zero time without visits
+	Oldest(ctx context.Context) (time.Time, error)
+	// DeleteBefore removes visits recorded before t and returns how many
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+}
+
+// RollupRepository stores hourly and daily aggregates of visits. Days are
+// rolled up whole, in UTC.
+type RollupRepository interface {
+	ListHourly(ctx context.Context, filter RollupFilter) ([]models.Rollup, error)
+	ListDaily(ctx context.Context, filter RollupFilter) ([]models.Rollup, error)
+	// ReplaceDay atomically replaces the rollups of the UTC day starting at day
+	ReplaceDay(ctx context.Context, day time.Time, hourly, daily []models.Rollup) error
+	// Watermark returns the end of the last rolled up day, or the zero time
+	Watermark(ctx context.Context) (time.Time, error)
+}
+
+// ClickRepository stores clicks on project links
+type ClickRepository interface {
+	Create(ctx context.Context, click *models.ProjectClick) error
+	// Count groups the clicks by project and target
+	Count(ctx context.Context, filter ClickFilter) ([]ClickCount, error)
+}
+
+// SearchRepository searches projects, skills and user profiles
+type SearchRepository interface {
+	// SearchPage returns the page of the matches of every term that the list
+	// query asks for, sorting and paging them in storage
+	SearchPage(ctx context.Context, query SearchQuery, list listing.Query) (listing.Page[models.SearchResult], error)
+}
+
+// APIKeyRepository stores hashed API keys
+type APIKeyRepository interface {
+	List(ctx context.Context) ([]models.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	Create(ctx context.Context, key *models.APIKey) error
+	MarkUsed(ctx context.Context, id string, at time.Time) error
+	Revoke(ctx context.Context, id string) error
+}
+
+// VisitorSaltRepository stores the daily salts behind visitor hashes. Days
+// are YYYY-MM-DD strings in UTC.
+type VisitorSaltRepository interface {
+	// Salt returns the salt of day, storing candidate if the day has none yet
+	Salt(ctx context.Context, day, candidate string) (string, error)
+	// DeleteBefore removes the salts of every day before day
+	DeleteBefore(ctx context.Context, day string) error
+}
+
+// Repositories groups every repository the API depends on
+type Repositories struct {
+	Users    UserRepository
+	Projects ProjectRepository
+	Skills   SkillRepository
+	Contacts ContactRepository
+	Visits   VisitRepository
+	Rollups  RollupRepository
+	Clicks   ClickRepository
+	Search   SearchRepository
+	Salts    VisitorSaltRepository
+	APIKeys  APIKeyRepository
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"portfolio-api/models"
+	"portfolio-api/search"
+)
+
+// The weights below mirror the search_vector columns of the Postgres schema
+
+func projectDocument(project models.Project) search.Document {
+	return search.Document{
+		Type:  models.SearchTypeProject,
+		ID:    project.ID,
+		Title: project.Title,
+		Owner: project.UserID,
+		Fields: []search.Field{
+			{Text: project.Title, Weight: search.WeightA},
+			{Text: strings.Join(project.TechStack, ", "), Weight: search.WeightB},
+			{Text: project.Description, Weight: search.WeightC},
+		},
+	}
+}
+
+func skillDocument(skill models.Skill) search.Document {
+	return search.Document{
+		Type:  models.SearchTypeSkill,
+		ID:    skill.ID,
+		Title: skill.Name,
+		Owner: skill.UserID,
+		Fields: []search.Field{
+			{Text: skill.Name, Weight: search.WeightA},
+			{Text: skill.Category, Weight: search.WeightB},
+			{Text: skill.Description, Weight: search.WeightC},
+		},
+	}
+}
+
+func userDocument(user models.User) search.Document {
+	return search.Document{
+		Type:  models.SearchTypeUser,
+		ID:    user.ID,
+		Title: user.Name,
+		Owner: user.ID,
+		Fields: []search.Field{
+			{Text: user.Name, Weight: search.WeightA},
+			{Text: strings.Join(user.Skills, ", "), Weight: search.WeightB},
+			{Text: strings.TrimSpace(user.Location + " " + user.Bio), Weight: search.WeightC},
+		},
+	}
+}
+
+// searchResult describes a matching document
+func searchResult(doc search.Document, rank float64, terms []string) models.SearchResult {
+	return models.SearchResult{
+		Type:    doc.Type,
+		ID:      doc.ID,
+		Title:   doc.Title,
+		Snippet: doc.Snippet(terms),
+		Rank:    math.Round(rank*1000) / 1000,
+		URL:     "/api/v1/" + doc.Type + "s/" + doc.ID,
+	}
+}
+
+// searchResults describes the hits of an index search
+func searchResults(hits []search.Hit, terms []string) []models.SearchResult {
+	results := make([]models.SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, searchResult(hit.Document, hit.Rank, terms))
+	}
+	return results
+}
+
+// hiddenOwners returns the IDs of the private users whose profiles, projects
+// and skills a query may not find
+func hiddenOwners(private []models.User, query SearchQuery) map[string]bool {
+	hidden := make(map[string]bool)
+	if !query.Visible {
+		return hidden
+	}
+	for _, user := range private {
+		if user.ID != query.ViewerID {
+			hidden[user.ID] = true
+		}
+	}
+	return hidden
+}
+
+// searchesType reports whether a query includes results of docType
+func searchesType(query SearchQuery, docType string) bool {
+	return len(query.Types) == 0 || slices.Contains(query.Types, docType)
+}
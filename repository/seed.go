@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"portfolio-api/models"
+)
+
+// SeedSampleData inserts the sample portfolio profile, projects and skills
+// into an empty store. It is a no-op once any user exists.
+func SeedSampleData(ctx context.Context, repos *Repositories) error {
+	users, err := repos.Users.List(ctx, UserFilter{})
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		return nil
+	}
+
+	owner := models.User{
+		Name:     "이혁주",
+		Email:    "hyoukjoo@example.com",
+		Role:     models.RoleAdmin,
+		Bio:      "Backend engineer specializing in Go, TypeScript, and cloud architecture",
+		Website:  "https://hyoukjoolee.github.io/portfolio",
+		Location: "Seoul, Korea",
+		Skills:   []string{"Go", "TypeScript", "Flutter", "AWS", "Docker", "PostgreSQL"},
+		IsPublic: true,
+	}
+	if err := repos.Users.Create(ctx, &owner); err != nil {
+		return err
+	}
+
+	projects := []models.Project{
+		{
+			Title:       "Portfolio API Server",
+			Description: "Go REST API with Supabase integration",
+			TechStack:   []string{"Go", "Supabase", "Railway"},
+			Status:      "completed",
+			Featured:    true,
+			LiveURL:     "https://portfolio-api.up.railway.app",
+			GithubURL:   "https://github.com/hyoukjoolee/portfolio-api",
+			StartDate:   time.Now().AddDate(0, 0, -7),
+		},
+		{
+			Title:       "Flutter Portfolio",
+			Description: "Responsive portfolio website with i18n",
+			TechStack:   []string{"Flutter", "Dart", "GitHub Pages"},
+			Status:      "completed",
+			Featured:    true,
+			LiveURL:     "https://hyoukjoolee.github.io/portfolio",
+			GithubURL:   "https://github.com/hyoukjoolee/portfolio",
+			StartDate:   time.Now().AddDate(0, 0, -60),
+		},
+	}
+	for i := range projects {
+		projects[i].UserID = owner.ID
+		projects[i].Publication = models.PublicationPublished
+		projects[i].PublishedAt = &projects[i].StartDate
+		if err := repos.Projects.Create(ctx, &projects[i]); err != nil {
+			return err
+		}
+	}
+
+	skills := []models.Skill{
+		{Name: "Go", Category: "backend", Level: "expert", YearsExp: 3, Featured: true, Color: "#00ADD8"},
+		{Name: "TypeScript", Category: "frontend", Level: "advanced", YearsExp: 4, Featured: true, Color: "#3178C6"},
+		{Name: "Flutter", Category: "mobile", Level: "advanced", YearsExp: 2, Featured: true, Color: "#02569B"},
+		{Name: "Supabase", Category: "database", Level: "intermediate", YearsExp: 1, Featured: true, Color: "#3ECF8E"},
+	}
+	for i := range skills {
+		skills[i].UserID = owner.ID
+		if err := repos.Skills.Create(ctx, &skills[i]); err != nil {
+			return err
+		}
+	}
+
+	log.Println("Sample data inserted successfully")
+	return nil
+}
+
+// AssignProjectSlugs gives projects created before slugs existed a slug
+// derived from their title
+func AssignProjectSlugs(ctx context.Context, repos *Repositories) error {
+	projects, err := repos.Projects.List(ctx, ProjectFilter{})
+	if err != nil {
+		return err
+	}
+
+	assigned := 0
+	for i := range projects {
+		if projects[i].Slug != "" {
+			continue
+		}
+		if err := repos.Projects.Update(ctx, &projects[i]); err != nil {
+			return err
+		}
+		assigned++
+	}
+	if assigned > 0 {
+		log.Printf("Assigned slugs to %d projects", assigned)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"portfolio-api/database"
+)
+
+// NewSQL returns repositories backed by a migrated Postgres, Supabase or SQLite database
+func NewSQL(conn *sql.DB, dialect database.Dialect) *Repositories {
+	db := &sqlDB{db: conn, dialect: dialect}
+	return &Repositories{
+		Users:    &sqlUserRepository{db: db},
+		Projects: &sqlProjectRepository{db: db},
+		Skills:   &sqlSkillRepository{db: db},
+		Contacts: &sqlContactRepository{db: db},
+		Visits:   &sqlVisitRepository{db: db},
+		Rollups:  &sqlRollupRepository{db: db},
+		Clicks:   &sqlClickRepository{db: db},
+		Search:   &sqlSearchRepository{db: db},
+		Salts:    &sqlVisitorSaltRepository{db: db},
+		APIKeys:  &sqlAPIKeyRepository{db: db},
+	}
+}
+
+// sqlDB runs queries written with Postgres $n placeholders against any dialect
+type sqlDB struct {
+	db      *sql.DB
+	dialect database.Dialect
+}
+
+func (d *sqlDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	query, args = d.dialect.Rebind(query, args)
+	return d.db.ExecContext(ctx, query, args...)
+}
+
+func (d *sqlDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	query, args = d.dialect.Rebind(query, args)
+	return d.db.QueryContext(ctx, query, args...)
+}
+
+func (d *sqlDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	query, args = d.dialect.Rebind(query, args)
+	return d.db.QueryRowContext(ctx, query, args...)
+}
+
+// withTx runs fn in a transaction, committing if it returns nil and rolling
+// back otherwise
+func (d *sqlDB) withTx(ctx context.Context, fn func(tx *sqlTx) error) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(&sqlTx{tx: tx, dialect: d.dialect}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// sqlTx is the transactional counterpart of sqlDB
+type sqlTx struct {
+	tx      *sql.Tx
+	dialect database.Dialect
+}
+
+func (t *sqlTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	query, args = t.dialect.Rebind(query, args)
+	return t.tx.ExecContext(ctx, query, args...)
+}
+
+func (t *sqlTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	query, args = t.dialect.Rebind(query, args)
+	return t.tx.QueryContext(ctx, query, args...)
+}
+
+func (t *sqlTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	query, args = t.dialect.Rebind(query, args)
+	return t.tx.QueryRowContext(ctx, query, args...)
+}
+
+// inet casts a placeholder to the IP address column type
+func (d *sqlDB) inet(placeholder string) string {
+	if d.dialect == database.Postgres {
+		return placeholder + "::inet"
+	}
+	return placeholder
+}
+
+// inetText selects an IP address column as plain text
+func (d *sqlDB) inetText(column string) string {
+	if d.dialect == database.Postgres {
+		return "host(" + column + ")"
+	}
+	return column
+}
+
+// now returns the timestamp written to created_at/updated_at columns; UTC keeps
+// SQLite's text timestamps in lexical order
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// validID reports whether id can be a primary key; IDs are UUIDs in every dialect
+func validID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+// nullString maps an empty string to SQL NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullTime maps a zero time to SQL NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// nullTimePtr maps a nil time pointer to SQL NULL
+func nullTimePtr(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return nullTime(*t)
+}
+
+// timePtr returns nil for SQL NULL and a pointer to the time otherwise
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// marshalStrings encodes a string slice for a JSONB column, never producing null
+func marshalStrings(values []string) (string, error) {
+	if values == nil {
+		values = []string{}
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// unmarshalStrings decodes a JSONB column into a string slice
+func unmarshalStrings(data []byte) ([]string, error) {
+	values := []string{}
+	if len(data) == 0 {
+		return values, nil
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = []string{}
+	}
+	return values, nil
+}
+
+// appendIn appends values to args and returns their comma separated placeholders
+// for an IN (...) clause
+func appendIn(args []interface{}, values []string) ([]interface{}, string) {
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		args = append(args, value)
+		placeholders[i] = "$" + strconv.Itoa(len(args))
+	}
+	return args, strings.Join(placeholders, ", ")
+}
+
+// likeEscaper escapes LIKE wildcards so search terms match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// containsPattern builds a pattern for LOWER(column) LIKE $n ESCAPE '\' that
+// matches term anywhere, case-insensitively in every dialect
+func containsPattern(term string) string {
+	return "%" + likeEscaper.Replace(strings.ToLower(term)) + "%"
+}
+
+// notFound converts sql.ErrNoRows into ErrNotFound
+func notFound(err error) error {
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	return err
+}
+
+// isUniqueViolation reports whether err is a Postgres or SQLite unique constraint failure
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	if pqErr, ok := err.(*pq.Error); ok {
+		return pqErr.Code == "23505"
+	}
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// requireAffected returns ErrNotFound when a statement touched no rows
+func requireAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
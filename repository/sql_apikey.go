@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"portfolio-api/models"
+)
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, created_at, last_used_at, revoked_at`
+
+type sqlAPIKeyRepository struct {
+	db *sqlDB
+}
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	var key models.APIKey
+	var userID sql.NullString
+	var lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(
+		&key.ID,
+		&userID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.CreatedAt,
+		&lastUsedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.UserID = userID.String
+	key.LastUsedAt = timePtr(lastUsedAt)
+	key.RevokedAt = timePtr(revokedAt)
+	return &key, nil
+}
+
+func (r *sqlAPIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *sqlAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE key_hash = $1"
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, keyHash))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return key, nil
+}
+
+func (r *sqlAPIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	key.ID = newID()
+	key.CreatedAt = now()
+
+	query := `
+		INSERT INTO api_keys (id, user_id, name, prefix, key_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		key.ID,
+		nullString(key.UserID),
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		key.CreatedAt,
+	)
+	if isUniqueViolation(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (r *sqlAPIKeyRepository) MarkUsed(ctx context.Context, id string, at time.Time) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+
+	result, err := r.db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = $1 WHERE id = $2", at.UTC(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func (r *sqlAPIKeyRepository) Revoke(ctx context.Context, id string) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL", now(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
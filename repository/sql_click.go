@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"portfolio-api/models"
+)
+
+type sqlClickRepository struct {
+	db *sqlDB
+}
+
+func (r *sqlClickRepository) Create(ctx context.Context, click *models.ProjectClick) error {
+	click.ID = newID()
+	if click.CreatedAt.IsZero() {
+		click.CreatedAt = now()
+	}
+
+	query := `
+		INSERT INTO project_clicks (id, project_id, target, visitor_id, referrer, is_bot, bot_reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		click.ID,
+		click.ProjectID,
+		click.Target,
+		nullString(click.VisitorID),
+		nullString(click.Referrer),
+		click.IsBot,
+		nullString(click.BotReason),
+		click.CreatedAt.UTC(),
+	)
+	return err
+}
+
+func (r *sqlClickRepository) Count(ctx context.Context, filter ClickFilter) ([]ClickCount, error) {
+	query := "SELECT project_id, target, COUNT(*) FROM project_clicks WHERE 1=1"
+	args := []interface{}{}
+
+	if len(filter.ProjectIDs) > 0 {
+		ids := make([]string, 0, len(filter.ProjectIDs))
+		for _, id := range filter.ProjectIDs {
+			if validID(id) {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return []ClickCount{}, nil
+		}
+		var in string
+		args, in = appendIn(args, ids)
+		query += " AND project_id IN (" + in + ")"
+	}
+	if filter.IsBot != nil {
+		args = append(args, *filter.IsBot)
+		query += " AND is_bot = $" + strconv.Itoa(len(args))
+	}
+
+	query += " GROUP BY project_id, target ORDER BY project_id, target"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []ClickCount{}
+	for rows.Next() {
+		var count ClickCount
+		if err := rows.Scan(&count.ProjectID, &count.Target, &count.Clicks); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"portfolio-api/listing"
+	"portfolio-api/models"
+)
+
+type sqlContactRepository struct {
+	db *sqlDB
+}
+
+// columns lists the selected columns in scanContact order
+func (r *sqlContactRepository) columns() string {
+	return `id, name, email, subject, message, COALESCE(status, 'unread'), created_at, read_at, replied_at,
+		COALESCE(` + r.db.inetText("ip_address") + `, ''), COALESCE(user_agent, ''), spam_score, spam_reasons`
+}
+
+// contactSearchColumns are matched by ContactFilter.Search
+var contactSearchColumns = []string{"name", "email", "subject", "message"}
+
+func scanContact(row rowScanner) (*models.ContactMessage, error) {
+	var message models.ContactMessage
+	var readAt, repliedAt sql.NullTime
+	var spamReasons []byte
+
+	err := row.Scan(
+		&message.ID,
+		&message.Name,
+		&message.Email,
+		&message.Subject,
+		&message.Message,
+		&message.Status,
+		&message.CreatedAt,
+		&readAt,
+		&repliedAt,
+		&message.IPAddress,
+		&message.UserAgent,
+		&message.SpamScore,
+		&spamReasons,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.SpamReasons, err = unmarshalStrings(spamReasons); err != nil {
+		return nil, err
+	}
+
+	message.ReadAt = timePtr(readAt)
+	message.RepliedAt = timePtr(repliedAt)
+	return &message, nil
+}
+
+func (r *sqlContactRepository) Create(ctx context.Context, message *models.ContactMessage) error {
+	spamReasons, err := marshalStrings(message.SpamReasons)
+	if err != nil {
+		return err
+	}
+
+	message.ID = newID()
+	message.CreatedAt = now()
+
+	query := `
+		INSERT INTO contact_messages (id, name, email, subject, message, status, created_at,
+			ip_address, user_agent, spam_score, spam_reasons)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, ` + r.db.inet("$8") + `, $9, $10, $11)`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		message.ID,
+		message.Name,
+		message.Email,
+		message.Subject,
+		message.Message,
+		message.Status,
+		message.CreatedAt,
+		nullString(message.IPAddress),
+		nullString(message.UserAgent),
+		message.SpamScore,
+		spamReasons,
+	)
+	return err
+}
+
+// where renders the conditions of a filter
+func (r *sqlContactRepository) where(filter ContactFilter) (string, []interface{}) {
+	where := "1=1"
+	args := []interface{}{}
+
+	if len(filter.Statuses) > 0 {
+		var in string
+		args, in = appendIn(args, filter.Statuses)
+		where += " AND status IN (" + in + ")"
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From.UTC())
+		where += " AND created_at >= $" + strconv.Itoa(len(args))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To.UTC())
+		where += " AND created_at < $" + strconv.Itoa(len(args))
+	}
+	if filter.Search != "" {
+		args = append(args, containsPattern(filter.Search))
+		pattern := "$" + strconv.Itoa(len(args))
+		conditions := []string{}
+		for _, column := range contactSearchColumns {
+			conditions = append(conditions, "LOWER("+column+") LIKE "+pattern+" ESCAPE '\\'")
+		}
+		where += " AND (" + strings.Join(conditions, " OR ") + ")"
+	}
+
+	return where, args
+}
+
+func (r *sqlContactRepository) ListPage(ctx context.Context, filter ContactFilter, query listing.Query) (listing.Page[models.ContactMessage], error) {
+	where, args := r.where(filter)
+	list := sqlList[models.ContactMessage]{
+		db:       r.db,
+		columns:  r.columns(),
+		from:     "contact_messages",
+		where:    where,
+		args:     args,
+		sort:     contactSortColumns,
+		resource: ContactList,
+		scan:     scanContact,
+	}
+	return list.page(ctx, query)
+}
+
+func (r *sqlContactRepository) Get(ctx context.Context, id string) (*models.ContactMessage, error) {
+	if !validID(id) {
+		return nil, ErrNotFound
+	}
+
+	query := "SELECT " + r.columns() + " FROM contact_messages WHERE id = $1"
+
+	message, err := scanContact(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return message, nil
+}
+
+func (r *sqlContactRepository) UpdateStatus(ctx context.Context, message *models.ContactMessage) error {
+	if !validID(message.ID) {
+		return ErrNotFound
+	}
+
+	query := "UPDATE contact_messages SET status = $1, read_at = $2, replied_at = $3 WHERE id = $4"
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		message.Status,
+		nullTimePtr(message.ReadAt),
+		nullTimePtr(message.RepliedAt),
+		message.ID,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func (r *sqlContactRepository) Delete(ctx context.Context, id string) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM contact_messages WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strconv"
+	"strings"
+
+	"portfolio-api/listing"
+)
+
+// sortKind is the type of a sortable column, telling how cursor values bind
+type sortKind int
+
+const (
+	sortText sortKind = iota
+	sortInteger
+	sortFloat
+	sortBool
+	sortTime
+)
+
+// sortColumn is the SQL expression a list sorts by for one field
+type sortColumn struct {
+	expr string
+	kind sortKind
+}
+
+// bind converts a cursor value into a query argument for the column
+func (col sortColumn) bind(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		switch col.kind {
+		case sortText:
+			return v, nil
+		case sortTime:
+			t, err := listing.CursorTime(v)
+			if err != nil {
+				return nil, listing.ErrInvalidCursor
+			}
+			return t, nil
+		}
+	case float64:
+		if col.kind == sortInteger && v == math.Trunc(v) {
+			return int64(v), nil
+		}
+		if col.kind == sortFloat {
+			return v, nil
+		}
+	case bool:
+		if col.kind == sortBool {
+			return v, nil
+		}
+	}
+	return nil, listing.ErrInvalidCursor
+}
+
+// sqlList is a list the database sorts and pages: the rows of from matching
+// where, selected as columns and read back with scan
+type sqlList[T any] struct {
+	db       *sqlDB
+	columns  string
+	from     string
+	where    string
+	args     []interface{}
+	sort     map[string]sortColumn
+	resource listing.Resource[T]
+	scan     func(rowScanner) (*T, error)
+	// cursorID validates the ID in a cursor; nil accepts row UUIDs
+	cursorID func(string) bool
+}
+
+// page fetches the page the query asks for. It counts the list, counts the
+// rows past the cursor when there is one, and reads the page itself.
+func (l sqlList[T]) page(ctx context.Context, query listing.Query) (listing.Page[T], error) {
+	var total int
+	err := l.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+l.from+" WHERE "+l.where, l.args...).Scan(&total)
+	if err != nil {
+		return listing.Page[T]{}, err
+	}
+
+	window := query.Window()
+	where, args := l.where, append([]interface{}{}, l.args...)
+	pastCursor := total
+	if window.Cursor != nil {
+		var predicate string
+		if predicate, args, err = l.pastCursor(window, args); err != nil {
+			return listing.Page[T]{}, err
+		}
+		where += " AND (" + predicate + ")"
+		if err := l.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+l.from+" WHERE "+where, args...).Scan(&pastCursor); err != nil {
+			return listing.Page[T]{}, err
+		}
+	}
+
+	// The sort values are selected too: cursors must carry the values the
+	// database compares, which can differ from the Go ones, as SQLite's LOWER
+	// only folds ASCII letters
+	columns := l.columns
+	for _, key := range window.Sort {
+		columns += ", " + l.sort[key.Field].expr
+	}
+
+	args = append(args, window.Limit, window.Offset)
+	rows, err := l.db.QueryContext(ctx,
+		"SELECT "+columns+" FROM "+l.from+" WHERE "+where+
+			" ORDER BY "+l.orderBy(window)+
+			" LIMIT $"+strconv.Itoa(len(args)-1)+" OFFSET $"+strconv.Itoa(len(args)),
+		args...)
+	if err != nil {
+		return listing.Page[T]{}, err
+	}
+	defer rows.Close()
+
+	items := []T{}
+	values := make(map[string][]interface{})
+	for rows.Next() {
+		row := sortValueScanner{rowScanner: rows}
+		for _, key := range window.Sort {
+			row.add(l.sort[key.Field].kind)
+		}
+		item, err := l.scan(&row)
+		if err != nil {
+			return listing.Page[T]{}, err
+		}
+		items = append(items, *item)
+		values[l.resource.ID(*item)] = row.values()
+	}
+	if err := rows.Err(); err != nil {
+		return listing.Page[T]{}, err
+	}
+
+	// Sort by the selected values when building cursors
+	resource := listing.Resource[T]{Sort: make(map[string]func(T) interface{}), ID: l.resource.ID}
+	for j, key := range query.Sort {
+		j := j
+		resource.Sort[key.Field] = func(item T) interface{} { return values[l.resource.ID(item)][j] }
+	}
+	return listing.Assemble(items, total, pastCursor, query, resource), nil
+}
+
+// sortValueScanner scans the sort values selected after a row's columns
+type sortValueScanner struct {
+	rowScanner
+	extra []interface{}
+}
+
+// add expects one more sort value of the given kind
+func (s *sortValueScanner) add(kind sortKind) {
+	switch kind {
+	case sortInteger:
+		s.extra = append(s.extra, &sql.NullInt64{})
+	case sortFloat:
+		s.extra = append(s.extra, &sql.NullFloat64{})
+	case sortBool:
+		s.extra = append(s.extra, &sql.NullBool{})
+	case sortTime:
+		s.extra = append(s.extra, &sql.NullTime{})
+	default:
+		s.extra = append(s.extra, &sql.NullString{})
+	}
+}
+
+func (s *sortValueScanner) Scan(dest ...interface{}) error {
+	return s.rowScanner.Scan(append(dest, s.extra...)...)
+}
+
+// values returns the scanned sort values, nil for NULL
+func (s *sortValueScanner) values() []interface{} {
+	values := make([]interface{}, len(s.extra))
+	for i, v := range s.extra {
+		switch v := v.(type) {
+		case *sql.NullInt64:
+			if v.Valid {
+				values[i] = v.Int64
+			}
+		case *sql.NullFloat64:
+			if v.Valid {
+				values[i] = v.Float64
+			}
+		case *sql.NullBool:
+			if v.Valid {
+				values[i] = v.Bool
+			}
+		case *sql.NullTime:
+			if v.Valid {
+				values[i] = v.Time
+			}
+		case *sql.NullString:
+			if v.Valid {
+				values[i] = v.String
+			}
+		}
+	}
+	return values
+}
+
+// orderBy renders the window order. NULLs sort before every value, as they
+// do in memory.
+func (l sqlList[T]) orderBy(window listing.Window) string {
+	terms := make([]string, 0, len(window.Sort)+1)
+	for _, key := range window.Sort {
+		if key.Desc {
+			terms = append(terms, l.sort[key.Field].expr+" DESC NULLS LAST")
+		} else {
+			terms = append(terms, l.sort[key.Field].expr+" ASC NULLS FIRST")
+		}
+	}
+	if window.IDDesc {
+		return strings.Join(append(terms, "id DESC"), ", ")
+	}
+	return strings.Join(append(terms, "id ASC"), ", ")
+}
+
+// pastCursor renders the condition that keeps the rows after the window's
+// cursor: those ordered after it by the first sort value that differs, or
+// by ID when every sort value is equal
+func (l sqlList[T]) pastCursor(window listing.Window, args []interface{}) (string, []interface{}, error) {
+	cursorID := l.cursorID
+	if cursorID == nil {
+		cursorID = validID
+	}
+	if !cursorID(window.Cursor.ID) {
+		return "", nil, listing.ErrInvalidCursor
+	}
+
+	var alternatives []string
+	var equal []string
+	for i, key := range window.Sort {
+		col := l.sort[key.Field]
+		value := window.Cursor.Values[i]
+
+		var after, same string
+		if value == nil {
+			// NULL sorts first: every value lies after it ascending, none descending
+			after, same = col.expr+" IS NOT NULL", col.expr+" IS NULL"
+			if key.Desc {
+				after = "1 = 0"
+			}
+		} else {
+			bound, err := col.bind(value)
+			if err != nil {
+				return "", nil, err
+			}
+			args = append(args, bound)
+			placeholder := "$" + strconv.Itoa(len(args))
+			after, same = col.expr+" > "+placeholder, col.expr+" = "+placeholder
+			if key.Desc {
+				after = "(" + col.expr + " IS NULL OR " + col.expr + " < " + placeholder + ")"
+			}
+		}
+
+		alternatives = append(alternatives, strings.Join(append(equal, after), " AND "))
+		equal = append(equal, same)
+	}
+
+	args = append(args, window.Cursor.ID)
+	after := "id > $" + strconv.Itoa(len(args))
+	if window.IDDesc {
+		after = "id < $" + strconv.Itoa(len(args))
+	}
+	alternatives = append(alternatives, strings.Join(append(equal, after), " AND "))
+
+	return "(" + strings.Join(alternatives, ") OR (") + ")", args, nil
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"portfolio-api/database"
+	"portfolio-api/listing"
+	"portfolio-api/models"
+)
+
+// newSQLiteRepositories returns repositories backed by a migrated SQLite file
+func newSQLiteRepositories(t *testing.T) *Repositories {
+	t.Helper()
+	db, dialect, err := database.Open(database.DriverSQLite, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := database.NewMigrator(db, dialect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return NewSQL(db, dialect)
+}
+
+// checkListPage checks, for each sort, that fetch orders the whole list the
+// way the in-memory paginator does, and that next and prev cursors and page
+// numbers visit it in that order
+func checkListPage[T any](t *testing.T, resource listing.Resource[T], total int, sorts []string, fetch func(listing.Query) (listing.Page[T], error)) {
+	t.Helper()
+	parse := func(values url.Values) listing.Query {
+		t.Helper()
+		query, err := listing.Parse(values, resource)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return query
+	}
+	ids := func(items []T) []string {
+		ids := []string{}
+		for _, item := range items {
+			ids = append(ids, resource.ID(item))
+		}
+		return ids
+	}
+
+	for _, sortParam := range sorts {
+		t.Run(sortParam, func(t *testing.T) {
+			all, err := fetch(parse(url.Values{"sort": {sortParam}, "limit": {"100"}}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := ids(listing.Paginate(all.Items, parse(url.Values{"sort": {sortParam}, "limit": {"100"}}), resource).Items)
+			if got := ids(all.Items); !reflect.DeepEqual(got, want) || all.Total != total {
+				t.Fatalf("order %v (total %d), want %v", got, all.Total, want)
+			}
+
+			values := url.Values{"sort": {sortParam}, "limit": {"4"}}
+			forward := []string{}
+			var page listing.Page[T]
+			for i := 0; i < 10; i++ {
+				if page, err = fetch(parse(values)); err != nil {
+					t.Fatal(err)
+				}
+				forward = append(forward, ids(page.Items)...)
+				envelope, _ := page.Envelope(&url.URL{})
+				if envelope.NextCursor == "" {
+					break
+				}
+				values.Set("cursor", envelope.NextCursor)
+			}
+			if !reflect.DeepEqual(forward, want) {
+				t.Errorf("next links visit %v, want %v", forward, want)
+			}
+
+			backward := ids(page.Items)
+			for i := 0; i < 10; i++ {
+				envelope, _ := page.Envelope(&url.URL{})
+				if envelope.PrevCursor == "" {
+					break
+				}
+				values.Set("cursor", envelope.PrevCursor)
+				if page, err = fetch(parse(values)); err != nil {
+					t.Fatal(err)
+				}
+				backward = append(ids(page.Items), backward...)
+			}
+			if !reflect.DeepEqual(backward, want) {
+				t.Errorf("prev links visit %v, want %v", backward, want)
+			}
+
+			values.Del("cursor")
+			values.Set("page", strconv.Itoa(3))
+			if page, err = fetch(parse(values)); err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(page.Items); !reflect.DeepEqual(got, want[8:12]) {
+				t.Errorf("page 3 = %v, want %v", got, want[8:12])
+			}
+		})
+	}
+}
+
+func TestSQLContactListPage(t *testing.T) {
+	ctx := context.Background()
+	contacts := newSQLiteRepositories(t).Contacts
+
+	names := []string{"alice", "Bob", "carol", "bob", "Émile"}
+	readAt := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 23; i++ {
+		message := models.ContactMessage{
+			Name:      names[i%len(names)],
+			Email:     "sender@example.com",
+			Subject:   "Hello",
+			Message:   "Hi",
+			Status:    models.ContactStatusUnread,
+			SpamScore: 10 * (i % 3),
+		}
+		if err := contacts.Create(ctx, &message); err != nil {
+			t.Fatal(err)
+		}
+		if i%4 != 0 {
+			at := readAt.Add(time.Duration(i%5) * time.Hour)
+			message.Status, message.ReadAt = models.ContactStatusRead, &at
+			if err := contacts.UpdateStatus(ctx, &message); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	sorts := []string{"-created_at", "read_at", "-read_at,name", "status,-spam_score", "spam_score,-read_at", "name"}
+	checkListPage(t, ContactList, 23, sorts, func(query listing.Query) (listing.Page[models.ContactMessage], error) {
+		return contacts.ListPage(ctx, ContactFilter{}, query)
+	})
+}
+
+func TestSQLProjectListPage(t *testing.T) {
+	ctx := context.Background()
+	projects := newSQLiteRepositories(t).Projects
+
+	titles := []string{"Atlas", "blog", "Compass", "atlas", "Éclair"}
+	started := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 21; i++ {
+		project := models.Project{
+			Title:       titles[i%len(titles)],
+			Description: "A project",
+			Status:      []string{"completed", "in-progress"}[i%2],
+			Featured:    i%3 == 0,
+			Publication: models.PublicationPublished,
+		}
+		if i%4 != 0 {
+			project.StartDate = started.AddDate(0, i%5, 0)
+		}
+		if i%3 == 1 {
+			ended := started.AddDate(1, i%2, 0)
+			project.EndDate = &ended
+		}
+		if err := projects.Create(ctx, &project); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sorts := []string{"-created_at", "title", "-featured,title", "start_date", "-start_date,status", "end_date,-featured"}
+	checkListPage(t, ProjectList, 21, sorts, func(query listing.Query) (listing.Page[models.Project], error) {
+		return projects.ListPage(ctx, ProjectFilter{}, query)
+	})
+}
+
+func TestSQLSkillListPage(t *testing.T) {
+	ctx := context.Background()
+	skills := newSQLiteRepositories(t).Skills
+
+	names := []string{"Go", "docker", "Kotlin", "go", "Élixir"}
+	for i := 0; i < 19; i++ {
+		skill := models.Skill{
+			Name:     names[i%len(names)],
+			Category: []string{"backend", "devops", "mobile"}[i%3],
+			Level:    []string{"beginner", "expert"}[i%2],
+			YearsExp: i % 4,
+			Featured: i%5 == 0,
+		}
+		if err := skills.Create(ctx, &skill); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sorts := []string{"-featured,-years_exp,name", "name", "category,-years_exp", "level,featured", "years_exp"}
+	checkListPage(t, SkillList, 19, sorts, func(query listing.Query) (listing.Page[models.Skill], error) {
+		return skills.ListPage(ctx, SkillFilter{}, query)
+	})
+}
+
+func TestSQLListPageRejectsForgedCursor(t *testing.T) {
+	users := newSQLiteRepositories(t).Users
+	query := listing.Query{
+		Limit:  10,
+		Sort:   UserList.DefaultSort,
+		Cursor: &listing.Cursor{Sort: "-created_at", Values: []interface{}{12.0}, ID: newID()},
+	}
+	if _, err := users.ListPage(context.Background(), UserFilter{}, query); err != listing.ErrInvalidCursor {
+		t.Errorf("got %v, want ErrInvalidCursor", err)
+	}
+}
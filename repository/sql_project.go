@@ -0,0 +1,425 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"portfolio-api/database"
+	"portfolio-api/listing"
+	"portfolio-api/models"
+	"portfolio-api/slug"
+)
+
+const projectColumns = `id, COALESCE(slug, ''), user_id, title, description, tech_stack, status, featured,
+	COALESCE(live_url, ''), COALESCE(github_url, ''), COALESCE(image_url, ''),
+	start_date, end_date, publication, publish_at, published_at, created_at, updated_at`
+
+// projectSearchColumns are matched by ProjectFilter.Search
+var projectSearchColumns = []string{"title", "description"}
+
+type sqlProjectRepository struct {
+	db *sqlDB
+}
+
+func scanProject(row rowScanner) (*models.Project, error) {
+	var project models.Project
+	var techStack []byte
+	var userID sql.NullString
+	var startDate, endDate, publishAt, publishedAt sql.NullTime
+
+	err := row.Scan(
+		&project.ID,
+		&project.Slug,
+		&userID,
+		&project.Title,
+		&project.Description,
+		&techStack,
+		&project.Status,
+		&project.Featured,
+		&project.LiveURL,
+		&project.GithubURL,
+		&project.ImageURL,
+		&startDate,
+		&endDate,
+		&project.Publication,
+		&publishAt,
+		&publishedAt,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if project.TechStack, err = unmarshalStrings(techStack); err != nil {
+		return nil, err
+	}
+	project.UserID = userID.String
+	project.StartDate = startDate.Time
+	project.EndDate = timePtr(endDate)
+	project.PublishAt = timePtr(publishAt)
+	project.PublishedAt = timePtr(publishedAt)
+
+	return &project, nil
+}
+
+// where renders the conditions of a filter
+func (r *sqlProjectRepository) where(filter ProjectFilter) (string, []interface{}, error) {
+	if filter.UserID != "" && !validID(filter.UserID) {
+		return "1=0", nil, nil
+	}
+
+	query := "1=1"
+	args := []interface{}{}
+
+	if filter.UserID != "" {
+		args = append(args, filter.UserID)
+		query += " AND user_id = $" + strconv.Itoa(len(args))
+	}
+	if len(filter.Statuses) > 0 {
+		var in string
+		args, in = appendIn(args, filter.Statuses)
+		query += " AND status IN (" + in + ")"
+	}
+	if filter.Featured != nil {
+		args = append(args, *filter.Featured)
+		query += " AND featured = $" + strconv.Itoa(len(args))
+	}
+	if len(filter.TechStack) > 0 {
+		var condition string
+		var err error
+		if args, condition, err = r.techCondition(args, filter.TechStack, filter.AllTech); err != nil {
+			return "", nil, err
+		}
+		query += " AND " + condition
+	}
+	if !filter.StartedAfter.IsZero() {
+		args = append(args, filter.StartedAfter.UTC())
+		query += " AND start_date >= $" + strconv.Itoa(len(args))
+	}
+	if !filter.EndedBefore.IsZero() {
+		args = append(args, filter.EndedBefore.UTC())
+		query += " AND end_date < $" + strconv.Itoa(len(args))
+	}
+	if filter.HasLiveURL != nil {
+		if *filter.HasLiveURL {
+			query += " AND COALESCE(live_url, '') <> ''"
+		} else {
+			query += " AND COALESCE(live_url, '') = ''"
+		}
+	}
+	if filter.Search != "" {
+		args = append(args, containsPattern(filter.Search))
+		pattern := "$" + strconv.Itoa(len(args))
+		conditions := []string{}
+		for _, column := range projectSearchColumns {
+			conditions = append(conditions, "LOWER("+column+") LIKE "+pattern+" ESCAPE '\\'")
+		}
+		query += " AND (" + strings.Join(conditions, " OR ") + ")"
+	}
+	if len(filter.Publications) > 0 {
+		var in string
+		args, in = appendIn(args, filter.Publications)
+		query += " AND publication IN (" + in + ")"
+	}
+	if filter.Visible {
+		if validID(filter.ViewerID) {
+			args = append(args, filter.ViewerID)
+			query += " AND (publication = 'published' OR user_id = $" + strconv.Itoa(len(args)) + ")"
+		} else {
+			query += " AND publication = 'published'"
+		}
+	}
+
+	return query, args, nil
+}
+
+func (r *sqlProjectRepository) List(ctx context.Context, filter ProjectFilter) ([]models.Project, error) {
+	where, args, err := r.where(filter)
+	if err != nil {
+		return nil, err
+	}
+	query := "SELECT " + projectColumns + " FROM projects WHERE " + where + " ORDER BY created_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := []models.Project{}
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, *project)
+	}
+
+	return projects, rows.Err()
+}
+
+func (r *sqlProjectRepository) ListPage(ctx context.Context, filter ProjectFilter, query listing.Query) (listing.Page[models.Project], error) {
+	where, args, err := r.where(filter)
+	if err != nil {
+		return listing.Page[models.Project]{}, err
+	}
+	list := sqlList[models.Project]{
+		db:       r.db,
+		columns:  projectColumns,
+		from:     "projects",
+		where:    where,
+		args:     args,
+		sort:     projectSortColumns,
+		resource: ProjectList,
+		scan:     scanProject,
+	}
+	return list.page(ctx, query)
+}
+
+// techCondition matches projects using any or all of the technologies. Postgres
+// tests JSONB containment, which the tech_stack GIN index serves; SQLite
+// looks the names up with json_each.
+func (r *sqlProjectRepository) techCondition(args []interface{}, techStack []string, all bool) ([]interface{}, string, error) {
+	if r.db.dialect == database.Postgres {
+		if all {
+			contained, err := marshalStrings(techStack)
+			if err != nil {
+				return nil, "", err
+			}
+			args = append(args, contained)
+			return args, "tech_stack @> $" + strconv.Itoa(len(args)) + "::jsonb", nil
+		}
+		conditions := make([]string, len(techStack))
+		for i, tech := range techStack {
+			contained, err := marshalStrings([]string{tech})
+			if err != nil {
+				return nil, "", err
+			}
+			args = append(args, contained)
+			conditions[i] = "tech_stack @> $" + strconv.Itoa(len(args)) + "::jsonb"
+		}
+		return args, "(" + strings.Join(conditions, " OR ") + ")", nil
+	}
+
+	if all {
+		conditions := make([]string, len(techStack))
+		for i, tech := range techStack {
+			args = append(args, tech)
+			conditions[i] = "EXISTS (SELECT 1 FROM json_each(projects.tech_stack) WHERE value = $" + strconv.Itoa(len(args)) + ")"
+		}
+		return args, "(" + strings.Join(conditions, " AND ") + ")", nil
+	}
+	var in string
+	args, in = appendIn(args, techStack)
+	return args, "EXISTS (SELECT 1 FROM json_each(projects.tech_stack) WHERE value IN (" + in + "))", nil
+}
+
+func (r *sqlProjectRepository) Get(ctx context.Context, id string) (*models.Project, error) {
+	if !validID(id) {
+		return nil, ErrNotFound
+	}
+
+	query := "SELECT " + projectColumns + " FROM projects WHERE id = $1"
+
+	project, err := scanProject(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return project, nil
+}
+
+func (r *sqlProjectRepository) GetBySlug(ctx context.Context, s string) (*models.Project, error) {
+	query := "SELECT " + projectColumns + " FROM projects WHERE slug = $1"
+	project, err := scanProject(r.db.QueryRowContext(ctx, query, s))
+	if !errors.Is(err, sql.ErrNoRows) {
+		return project, err
+	}
+
+	query = "SELECT " + projectColumns + " FROM projects WHERE id = (SELECT project_id FROM project_slugs WHERE slug = $1)"
+	project, err = scanProject(r.db.QueryRowContext(ctx, query, s))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return project, nil
+}
+
+// claimSlug gives a project without a slug a unique one derived from its
+// title, avoiding the current and retired slugs of other projects, and drops
+// the slug from the retired ones so it resolves to this project alone
+func claimSlug(ctx context.Context, tx *sqlTx, project *models.Project) error {
+	if project.Slug == "" {
+		base := slug.Make(project.Title)
+		rows, err := tx.QueryContext(ctx, `
+			SELECT slug FROM projects WHERE (slug = $1 OR slug LIKE $2) AND id <> $3
+			UNION
+			SELECT slug FROM project_slugs WHERE (slug = $1 OR slug LIKE $2) AND project_id <> $3`,
+			base, base+"-%", project.ID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		taken := make(map[string]bool)
+		for rows.Next() {
+			var s string
+			if err := rows.Scan(&s); err != nil {
+				return err
+			}
+			taken[s] = true
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		project.Slug = slug.Unique(base, taken)
+	}
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM project_slugs WHERE slug = $1", project.Slug)
+	return err
+}
+
+func (r *sqlProjectRepository) Create(ctx context.Context, project *models.Project) error {
+	techStack, err := marshalStrings(project.TechStack)
+	if err != nil {
+		return err
+	}
+
+	project.ID = newID()
+	project.CreatedAt = now()
+	project.UpdatedAt = project.CreatedAt
+
+	query := `
+		INSERT INTO projects (id, slug, user_id, title, description, tech_stack, status, featured, live_url,
+			github_url, image_url, start_date, end_date, publication, publish_at, published_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+
+	return r.db.withTx(ctx, func(tx *sqlTx) error {
+		if err := claimSlug(ctx, tx, project); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			project.ID,
+			project.Slug,
+			nullString(project.UserID),
+			project.Title,
+			project.Description,
+			techStack,
+			project.Status,
+			project.Featured,
+			nullString(project.LiveURL),
+			nullString(project.GithubURL),
+			nullString(project.ImageURL),
+			nullTime(project.StartDate),
+			nullTimePtr(project.EndDate),
+			project.Publication,
+			nullTimePtr(project.PublishAt),
+			nullTimePtr(project.PublishedAt),
+			project.CreatedAt,
+			project.UpdatedAt,
+		)
+		if isUniqueViolation(err) {
+			return ErrConflict
+		}
+		return err
+	})
+}
+
+func (r *sqlProjectRepository) Update(ctx context.Context, project *models.Project) error {
+	if !validID(project.ID) {
+		return ErrNotFound
+	}
+
+	techStack, err := marshalStrings(project.TechStack)
+	if err != nil {
+		return err
+	}
+
+	project.UpdatedAt = now()
+
+	query := `
+		UPDATE projects
+		SET slug = $1, title = $2, description = $3, tech_stack = $4, status = $5, featured = $6,
+			live_url = $7, github_url = $8, image_url = $9, start_date = $10, end_date = $11,
+			publication = $12, publish_at = $13, published_at = $14, updated_at = $15
+		WHERE id = $16`
+
+	return r.db.withTx(ctx, func(tx *sqlTx) error {
+		var current sql.NullString
+		err := tx.QueryRowContext(ctx, "SELECT slug FROM projects WHERE id = $1", project.ID).Scan(&current)
+		if err != nil {
+			return notFound(err)
+		}
+		renamed := project.Slug == "" || project.Slug != current.String
+		if renamed {
+			if err := claimSlug(ctx, tx, project); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			query,
+			project.Slug,
+			project.Title,
+			project.Description,
+			techStack,
+			project.Status,
+			project.Featured,
+			nullString(project.LiveURL),
+			nullString(project.GithubURL),
+			nullString(project.ImageURL),
+			nullTime(project.StartDate),
+			nullTimePtr(project.EndDate),
+			project.Publication,
+			nullTimePtr(project.PublishAt),
+			nullTimePtr(project.PublishedAt),
+			project.UpdatedAt,
+			project.ID,
+		)
+		if isUniqueViolation(err) {
+			return ErrConflict
+		}
+		if err != nil || !renamed || current.String == "" {
+			return err
+		}
+
+		// Keep the old slug leading here
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO project_slugs (slug, project_id, created_at) VALUES ($1, $2, $3)
+			ON CONFLICT (slug) DO UPDATE SET project_id = excluded.project_id, created_at = excluded.created_at`,
+			current.String, project.ID, project.UpdatedAt)
+		return err
+	})
+}
+
+func (r *sqlProjectRepository) PublishDue(ctx context.Context, now time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE projects
+		SET publication = $1, published_at = publish_at, publish_at = NULL, updated_at = $2
+		WHERE publication = $3 AND publish_at <= $2`,
+		models.PublicationPublished, now, models.PublicationScheduled)
+	if err != nil {
+		return 0, err
+	}
+	published, err := result.RowsAffected()
+	return int(published), err
+}
+
+func (r *sqlProjectRepository) Delete(ctx context.Context, id string) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM projects WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
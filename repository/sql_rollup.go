@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"portfolio-api/models"
+)
+
+type sqlRollupRepository struct {
+	db *sqlDB
+}
+
+// rollupTotal is the dimension of the per-period totals; every rolled up day
+// has at least one daily total row
+const rollupTotal = "total"
+
+const rollupColumns = `period_start, is_bot, dimension, value, views, visitors,
+	new_visitors, sessions, bounces, session_pages, session_seconds`
+
+func (r *sqlRollupRepository) ListHourly(ctx context.Context, filter RollupFilter) ([]models.Rollup, error) {
+	return r.list(ctx, "analytics_hourly", filter)
+}
+
+func (r *sqlRollupRepository) ListDaily(ctx context.Context, filter RollupFilter) ([]models.Rollup, error) {
+	return r.list(ctx, "analytics_daily", filter)
+}
+
+func (r *sqlRollupRepository) list(ctx context.Context, table string, filter RollupFilter) ([]models.Rollup, error) {
+	query := "SELECT " + rollupColumns + " FROM " + table + " WHERE 1=1"
+	args := []interface{}{}
+
+	if !filter.From.IsZero() {
+		args = append(args, filter.From.UTC())
+		query += " AND period_start >= $" + strconv.Itoa(len(args))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To.UTC())
+		query += " AND period_start < $" + strconv.Itoa(len(args))
+	}
+	if filter.IsBot != nil {
+		args = append(args, *filter.IsBot)
+		query += " AND is_bot = $" + strconv.Itoa(len(args))
+	}
+	if len(filter.Dimensions) > 0 {
+		var in string
+		args, in = appendIn(args, filter.Dimensions)
+		query += " AND dimension IN (" + in + ")"
+	}
+
+	query += " ORDER BY period_start, is_bot, dimension, value"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rollups := []models.Rollup{}
+	for rows.Next() {
+		var rollup models.Rollup
+		err := rows.Scan(
+			&rollup.PeriodStart,
+			&rollup.IsBot,
+			&rollup.Dimension,
+			&rollup.Value,
+			&rollup.Views,
+			&rollup.Visitors,
+			&rollup.NewVisitors,
+			&rollup.Sessions,
+			&rollup.Bounces,
+			&rollup.SessionPages,
+			&rollup.SessionSeconds,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, rollup)
+	}
+
+	return rollups, rows.Err()
+}
+
+func (r *sqlRollupRepository) ReplaceDay(ctx context.Context, day time.Time, hourly, daily []models.Rollup) error {
+	start := day.UTC()
+	end := start.AddDate(0, 0, 1)
+
+	return r.db.withTx(ctx, func(tx *sqlTx) error {
+		for table, rollups := range map[string][]models.Rollup{"analytics_hourly": hourly, "analytics_daily": daily} {
+			_, err := tx.ExecContext(ctx,
+				"DELETE FROM "+table+" WHERE period_start >= $1 AND period_start < $2",
+				start, end,
+			)
+			if err != nil {
+				return err
+			}
+
+			query := "INSERT INTO " + table + " (" + rollupColumns + `)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+			for _, rollup := range rollups {
+				_, err := tx.ExecContext(ctx, query,
+					rollup.PeriodStart.UTC(),
+					rollup.IsBot,
+					rollup.Dimension,
+					rollup.Value,
+					rollup.Views,
+					rollup.Visitors,
+					rollup.NewVisitors,
+					rollup.Sessions,
+					rollup.Bounces,
+					rollup.SessionPages,
+					rollup.SessionSeconds,
+				)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (r *sqlRollupRepository) Watermark(ctx context.Context) (time.Time, error) {
+	var periodStart time.Time
+	err := r.db.QueryRowContext(ctx,
+		"SELECT period_start FROM analytics_daily WHERE dimension = $1 ORDER BY period_start DESC LIMIT 1",
+		rollupTotal,
+	).Scan(&periodStart)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return periodStart.UTC().AddDate(0, 0, 1), nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import "context"
+
+type sqlVisitorSaltRepository struct {
+	db *sqlDB
+}
+
+func (r *sqlVisitorSaltRepository) Salt(ctx context.Context, day, candidate string) (string, error) {
+	// Another instance may have stored the day's salt first; keep its value
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO visitor_salts (day, salt, created_at) VALUES ($1, $2, $3) ON CONFLICT (day) DO NOTHING`,
+		day, candidate, now(),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	var salt string
+	err = r.db.QueryRowContext(ctx, `SELECT salt FROM visitor_salts WHERE day = $1`, day).Scan(&salt)
+	return salt, err
+}
+
+func (r *sqlVisitorSaltRepository) DeleteBefore(ctx context.Context, day string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM visitor_salts WHERE day < $1`, day)
+	return err
+}
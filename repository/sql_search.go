@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"portfolio-api/database"
+	"portfolio-api/listing"
+	"portfolio-api/models"
+	"portfolio-api/search"
+)
+
+// Each searchable table as type, ID, title, the rest of its weight B text,
+// its weight C text and rank against the query q, rounded as results show
+// it, then the result's list key; the visibility condition on the owner
+// column follows
+var searchSelects = map[string]string{
+	models.SearchTypeProject: `SELECT 'project' AS type, id::text AS doc_id, title,
+		array_to_string(ARRAY(SELECT jsonb_array_elements_text(tech_stack)), ', ') AS weight_b,
+		description AS weight_c, ROUND(ts_rank(search_vector, q.query)::numeric, 3)::float8 AS rank,
+		'project/' || id::text AS id
+		FROM projects, q WHERE search_vector @@ q.query AND publication = 'published' AND `,
+	models.SearchTypeSkill: `SELECT 'skill', id::text, name, category, COALESCE(description, ''),
+		ROUND(ts_rank(search_vector, q.query)::numeric, 3)::float8, 'skill/' || id::text
+		FROM skills, q WHERE search_vector @@ q.query AND `,
+	models.SearchTypeUser: `SELECT 'user', id::text, name,
+		array_to_string(ARRAY(SELECT jsonb_array_elements_text(skills)), ', '),
+		concat_ws(' ', location, bio), ROUND(ts_rank(search_vector, q.query)::numeric, 3)::float8,
+		'user/' || id::text
+		FROM users, q WHERE search_vector @@ q.query AND `,
+}
+
+// searchOwners names the column holding the user each table belongs to
+var searchOwners = map[string]string{
+	models.SearchTypeProject: "user_id",
+	models.SearchTypeSkill:   "user_id",
+	models.SearchTypeUser:    "id",
+}
+
+type sqlSearchRepository struct {
+	db *sqlDB
+}
+
+func (r *sqlSearchRepository) SearchPage(ctx context.Context, query SearchQuery, list listing.Query) (listing.Page[models.SearchResult], error) {
+	if len(query.Terms) == 0 {
+		return listing.Paginate([]models.SearchResult{}, list, SearchList), nil
+	}
+	if r.db.dialect != database.Postgres {
+		results, err := r.searchIndex(ctx, query)
+		if err != nil {
+			return listing.Page[models.SearchResult]{}, err
+		}
+		return listing.Paginate(results, list, SearchList), nil
+	}
+
+	// Terms hold only letters and digits, so they cannot inject tsquery operators
+	prefixes := make([]string, len(query.Terms))
+	for i, term := range query.Terms {
+		prefixes[i] = term + ":*"
+	}
+	args := []interface{}{strings.Join(prefixes, " & ")}
+
+	visible := "SELECT id FROM users"
+	if query.Visible {
+		visible += " WHERE is_public"
+		if validID(query.ViewerID) {
+			args = append(args, query.ViewerID)
+			visible += " OR id = $2"
+		}
+	}
+	selects := []string{}
+	for _, docType := range models.SearchTypes {
+		if searchesType(query, docType) {
+			owner := searchOwners[docType]
+			selects = append(selects, searchSelects[docType]+"("+owner+" IS NULL OR "+owner+" IN ("+visible+"))")
+		}
+	}
+
+	results := sqlList[models.SearchResult]{
+		db:      r.db,
+		columns: "type, doc_id, title, weight_b, weight_c, rank",
+		from: "(WITH q AS (SELECT to_tsquery('simple', $1) AS query) " +
+			strings.Join(selects, " UNION ALL ") + ") AS results",
+		where:    "1=1",
+		args:     args,
+		sort:     searchSortColumns,
+		resource: SearchList,
+		scan: func(row rowScanner) (*models.SearchResult, error) {
+			var doc search.Document
+			var weightB, weightC string
+			var rank float64
+			if err := row.Scan(&doc.Type, &doc.ID, &doc.Title, &weightB, &weightC, &rank); err != nil {
+				return nil, err
+			}
+			doc.Fields = []search.Field{
+				{Text: doc.Title, Weight: search.WeightA},
+				{Text: weightB, Weight: search.WeightB},
+				{Text: weightC, Weight: search.WeightC},
+			}
+			result := searchResult(doc, rank, query.Terms)
+			return &result, nil
+		},
+		cursorID: validSearchKey,
+	}
+	return results.page(ctx, list)
+}
+
+// searchIndex serves SQLite, which has no tsvector: portfolios are small
+// enough to index the searchable rows on every search
+func (r *sqlSearchRepository) searchIndex(ctx context.Context, query SearchQuery) ([]models.SearchResult, error) {
+	private := false
+	users, err := (&sqlUserRepository{db: r.db}).List(ctx, UserFilter{IsPublic: &private})
+	if err != nil {
+		return nil, err
+	}
+	hidden := hiddenOwners(users, query)
+	index := search.NewIndex()
+
+	if searchesType(query, models.SearchTypeProject) {
+		projects, err := (&sqlProjectRepository{db: r.db}).List(ctx, ProjectFilter{Visible: true})
+		if err != nil {
+			return nil, err
+		}
+		for _, project := range projects {
+			if !hidden[project.UserID] {
+				index.Put(projectDocument(project))
+			}
+		}
+	}
+	if searchesType(query, models.SearchTypeSkill) {
+		skills, err := (&sqlSkillRepository{db: r.db}).List(ctx, SkillFilter{})
+		if err != nil {
+			return nil, err
+		}
+		for _, skill := range skills {
+			if !hidden[skill.UserID] {
+				index.Put(skillDocument(skill))
+			}
+		}
+	}
+	if searchesType(query, models.SearchTypeUser) {
+		users, err := (&sqlUserRepository{db: r.db}).List(ctx, UserFilter{})
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			if !hidden[user.ID] {
+				index.Put(userDocument(user))
+			}
+		}
+	}
+
+	return searchResults(index.Search(query.Terms, query.Types), query.Terms), nil
+}
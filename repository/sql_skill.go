@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"portfolio-api/listing"
+	"portfolio-api/models"
+)
+
+const skillColumns = `id, user_id, name, category, level, COALESCE(years_exp, 0), COALESCE(featured, false),
+	COALESCE(icon_url, ''), COALESCE(color, ''), COALESCE(description, '')`
+
+type sqlSkillRepository struct {
+	db *sqlDB
+}
+
+func scanSkill(row rowScanner) (*models.Skill, error) {
+	var skill models.Skill
+	var userID sql.NullString
+
+	err := row.Scan(
+		&skill.ID,
+		&userID,
+		&skill.Name,
+		&skill.Category,
+		&skill.Level,
+		&skill.YearsExp,
+		&skill.Featured,
+		&skill.Icon,
+		&skill.Color,
+		&skill.Description,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	skill.UserID = userID.String
+	return &skill, nil
+}
+
+// where renders the conditions of a filter
+func (r *sqlSkillRepository) where(filter SkillFilter) (string, []interface{}) {
+	if filter.UserID != "" && !validID(filter.UserID) {
+		return "1=0", nil
+	}
+
+	query := "1=1"
+	args := []interface{}{}
+
+	if filter.UserID != "" {
+		args = append(args, filter.UserID)
+		query += " AND user_id = $" + strconv.Itoa(len(args))
+	}
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		query += " AND category = $" + strconv.Itoa(len(args))
+	}
+	if filter.Featured != nil {
+		args = append(args, *filter.Featured)
+		query += " AND featured = $" + strconv.Itoa(len(args))
+	}
+
+	return query, args
+}
+
+func (r *sqlSkillRepository) List(ctx context.Context, filter SkillFilter) ([]models.Skill, error) {
+	where, args := r.where(filter)
+	query := "SELECT " + skillColumns + " FROM skills WHERE " + where + " ORDER BY featured DESC, years_exp DESC, name"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	skills := []models.Skill{}
+	for rows.Next() {
+		skill, err := scanSkill(rows)
+		if err != nil {
+			return nil, err
+		}
+		skills = append(skills, *skill)
+	}
+
+	return skills, rows.Err()
+}
+
+func (r *sqlSkillRepository) ListPage(ctx context.Context, filter SkillFilter, query listing.Query) (listing.Page[models.Skill], error) {
+	where, args := r.where(filter)
+	list := sqlList[models.Skill]{
+		db:       r.db,
+		columns:  skillColumns,
+		from:     "skills",
+		where:    where,
+		args:     args,
+		sort:     skillSortColumns,
+		resource: SkillList,
+		scan:     scanSkill,
+	}
+	return list.page(ctx, query)
+}
+
+func (r *sqlSkillRepository) Get(ctx context.Context, id string) (*models.Skill, error) {
+	if !validID(id) {
+		return nil, ErrNotFound
+	}
+
+	query := "SELECT " + skillColumns + " FROM skills WHERE id = $1"
+
+	skill, err := scanSkill(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return skill, nil
+}
+
+func (r *sqlSkillRepository) Create(ctx context.Context, skill *models.Skill) error {
+	skill.ID = newID()
+
+	query := `
+		INSERT INTO skills (id, user_id, name, category, level, years_exp, featured, icon_url, color, description,
+			created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		skill.ID,
+		nullString(skill.UserID),
+		skill.Name,
+		skill.Category,
+		skill.Level,
+		skill.YearsExp,
+		skill.Featured,
+		nullString(skill.Icon),
+		nullString(skill.Color),
+		nullString(skill.Description),
+		now(),
+	)
+	return err
+}
+
+func (r *sqlSkillRepository) Delete(ctx context.Context, id string) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM skills WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
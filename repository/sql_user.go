@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"portfolio-api/listing"
+	"portfolio-api/models"
+)
+
+const userColumns = `id, email, name, role, avatar_url, bio, website, location, skills, is_public, created_at, updated_at`
+
+type sqlUserRepository struct {
+	db *sqlDB
+}
+
+// userRow is the database shape of a users row; nullable columns are mapped
+// to their zero values and the JSONB skills column is decoded in toModel
+type userRow struct {
+	ID        string
+	Email     string
+	Name      string
+	Role      string
+	AvatarURL sql.NullString
+	Bio       sql.NullString
+	Website   sql.NullString
+	Location  sql.NullString
+	Skills    []byte
+	IsPublic  sql.NullBool
+	CreatedAt sql.NullTime
+	UpdatedAt sql.NullTime
+}
+
+func (r *userRow) fields() []interface{} {
+	return []interface{}{
+		&r.ID,
+		&r.Email,
+		&r.Name,
+		&r.Role,
+		&r.AvatarURL,
+		&r.Bio,
+		&r.Website,
+		&r.Location,
+		&r.Skills,
+		&r.IsPublic,
+		&r.CreatedAt,
+		&r.UpdatedAt,
+	}
+}
+
+func (r *userRow) toModel() (*models.User, error) {
+	skills, err := unmarshalStrings(r.Skills)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.User{
+		ID:        r.ID,
+		Email:     r.Email,
+		Name:      r.Name,
+		Role:      r.Role,
+		AvatarURL: r.AvatarURL.String,
+		Bio:       r.Bio.String,
+		Website:   r.Website.String,
+		Location:  r.Location.String,
+		Skills:    skills,
+		IsPublic:  r.IsPublic.Bool,
+		CreatedAt: r.CreatedAt.Time,
+		UpdatedAt: r.UpdatedAt.Time,
+	}, nil
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	var r userRow
+	if err := row.Scan(r.fields()...); err != nil {
+		return nil, err
+	}
+	return r.toModel()
+}
+
+// where renders the conditions of a filter
+func (r *sqlUserRepository) where(filter UserFilter) (string, []interface{}) {
+	where := "1=1"
+	args := []interface{}{}
+
+	if filter.IsPublic != nil {
+		args = append(args, *filter.IsPublic)
+		where += " AND is_public = $" + strconv.Itoa(len(args))
+	}
+
+	return where, args
+}
+
+func (r *sqlUserRepository) List(ctx context.Context, filter UserFilter) ([]models.User, error) {
+	where, args := r.where(filter)
+	query := "SELECT " + userColumns + " FROM users WHERE " + where + " ORDER BY created_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *sqlUserRepository) ListPage(ctx context.Context, filter UserFilter, query listing.Query) (listing.Page[models.User], error) {
+	where, args := r.where(filter)
+	list := sqlList[models.User]{
+		db:       r.db,
+		columns:  userColumns,
+		from:     "users",
+		where:    where,
+		args:     args,
+		sort:     userSortColumns,
+		resource: UserList,
+		scan:     scanUser,
+	}
+	return list.page(ctx, query)
+}
+
+func (r *sqlUserRepository) Get(ctx context.Context, id string) (*models.User, error) {
+	if !validID(id) {
+		return nil, ErrNotFound
+	}
+
+	query := "SELECT " + userColumns + " FROM users WHERE id = $1"
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return user, nil
+}
+
+func (r *sqlUserRepository) Create(ctx context.Context, user *models.User) error {
+	skills, err := marshalStrings(user.Skills)
+	if err != nil {
+		return err
+	}
+
+	user.ID = newID()
+	user.CreatedAt = now()
+	user.UpdatedAt = user.CreatedAt
+
+	query := `
+		INSERT INTO users (id, email, name, role, avatar_url, bio, website, location, skills, is_public, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		user.ID,
+		user.Email,
+		user.Name,
+		user.Role,
+		nullString(user.AvatarURL),
+		nullString(user.Bio),
+		nullString(user.Website),
+		nullString(user.Location),
+		skills,
+		user.IsPublic,
+		user.CreatedAt,
+		user.UpdatedAt,
+	)
+
+	if isUniqueViolation(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (r *sqlUserRepository) Update(ctx context.Context, user *models.User) error {
+	if !validID(user.ID) {
+		return ErrNotFound
+	}
+
+	skills, err := marshalStrings(user.Skills)
+	if err != nil {
+		return err
+	}
+
+	user.UpdatedAt = now()
+
+	query := `
+		UPDATE users
+		SET email = $1, name = $2, role = $3, avatar_url = $4, bio = $5, website = $6,
+			location = $7, skills = $8, is_public = $9, updated_at = $10
+		WHERE id = $11`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		user.Email,
+		user.Name,
+		user.Role,
+		nullString(user.AvatarURL),
+		nullString(user.Bio),
+		nullString(user.Website),
+		nullString(user.Location),
+		skills,
+		user.IsPublic,
+		user.UpdatedAt,
+		user.ID,
+	)
+
+	if isUniqueViolation(err) {
+		return ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func (r *sqlUserRepository) Delete(ctx context.Context, id string) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"portfolio-api/models"
+)
+
+type sqlVisitRepository struct {
+	db *sqlDB
+}
+
+const visitColumns = `id, page, COALESCE(referrer, ''), COALESCE(user_agent, ''),
+	COALESCE(visitor_id, ''), COALESCE(country, ''), COALESCE(city, ''),
+	COALESCE(browser, ''), COALESCE(browser_version, ''), COALESCE(os, ''),
+	COALESCE(device_type, ''), is_bot, COALESCE(bot_reason, ''),
+	COALESCE(referrer_host, ''), COALESCE(channel, ''), COALESCE(utm_source, ''),
+	COALESCE(utm_medium, ''), COALESCE(utm_campaign, ''), COALESCE(project_id, ''),
+	created_at, ended_at`
+
+func scanVisit(row rowScanner) (*models.Visit, error) {
+	var visit models.Visit
+	var endedAt sql.NullTime
+
+	err := row.Scan(
+		&visit.ID,
+		&visit.Page,
+		&visit.Referrer,
+		&visit.UserAgent,
+		&visit.VisitorID,
+		&visit.Country,
+		&visit.City,
+		&visit.Browser,
+		&visit.BrowserVersion,
+		&visit.OS,
+		&visit.DeviceType,
+		&visit.IsBot,
+		&visit.BotReason,
+		&visit.ReferrerHost,
+		&visit.Channel,
+		&visit.UTMSource,
+		&visit.UTMMedium,
+		&visit.UTMCampaign,
+		&visit.ProjectID,
+		&visit.CreatedAt,
+		&endedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	visit.EndedAt = timePtr(endedAt)
+	return &visit, nil
+}
+
+func (r *sqlVisitRepository) Create(ctx context.Context, visit *models.Visit) error {
+	visit.ID = newID()
+	if visit.CreatedAt.IsZero() {
+		visit.CreatedAt = now()
+	}
+
+	query := `
+		INSERT INTO analytics (id, page, referrer, user_agent, visitor_id, country, city,
+			browser, browser_version, os, device_type, is_bot, bot_reason,
+			referrer_host, channel, utm_source, utm_medium, utm_campaign, project_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		visit.ID,
+		visit.Page,
+		nullString(visit.Referrer),
+		nullString(visit.UserAgent),
+		nullString(visit.VisitorID),
+		nullString(visit.Country),
+		nullString(visit.City),
+		nullString(visit.Browser),
+		nullString(visit.BrowserVersion),
+		nullString(visit.OS),
+		nullString(visit.DeviceType),
+		visit.IsBot,
+		nullString(visit.BotReason),
+		nullString(visit.ReferrerHost),
+		nullString(visit.Channel),
+		nullString(visit.UTMSource),
+		nullString(visit.UTMMedium),
+		nullString(visit.UTMCampaign),
+		nullString(visit.ProjectID),
+		visit.CreatedAt.UTC(),
+	)
+	return err
+}
+
+func (r *sqlVisitRepository) Get(ctx context.Context, id string) (*models.Visit, error) {
+	if !validID(id) {
+		return nil, ErrNotFound
+	}
+
+	query := "SELECT " + visitColumns + " FROM analytics WHERE id = $1"
+
+	visit, err := scanVisit(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return visit, nil
+}
+
+func (r *sqlVisitRepository) List(ctx context.Context, filter VisitFilter) ([]models.Visit, error) {
+	query := "SELECT " + visitColumns + " FROM analytics WHERE 1=1"
+	args := []interface{}{}
+
+	if !filter.From.IsZero() {
+		args = append(args, filter.From.UTC())
+		query += " AND created_at >= $" + strconv.Itoa(len(args))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To.UTC())
+		query += " AND created_at < $" + strconv.Itoa(len(args))
+	}
+	if filter.IsBot != nil {
+		args = append(args, *filter.IsBot)
+		query += " AND is_bot = $" + strconv.Itoa(len(args))
+	}
+
+	query += " ORDER BY created_at"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	visits := []models.Visit{}
+	for rows.Next() {
+		visit, err := scanVisit(rows)
+		if err != nil {
+			return nil, err
+		}
+		visits = append(visits, *visit)
+	}
+
+	return visits, rows.Err()
+}
+
+func (r *sqlVisitRepository) SetEndedAt(ctx context.Context, id string, at time.Time) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+
+	result, err := r.db.ExecContext(ctx, "UPDATE analytics SET ended_at = $1 WHERE id = $2", at.UTC(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func (r *sqlVisitRepository) Oldest(ctx context.Context) (time.Time, error) {
+	// MIN() loses the column type in SQLite, so order instead
+	var createdAt time.Time
+	err := r.db.QueryRowContext(ctx, "SELECT created_at FROM analytics ORDER BY created_at LIMIT 1").Scan(&createdAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	return createdAt, err
+}
+
+func (r *sqlVisitRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM analytics WHERE created_at < $1", t.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,134 @@
+// Package rollup aggregates recorded visits into hourly and daily rollups in
+// the background and enforces the retention of raw visits
+package rollup
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"portfolio-api/models"
+	"portfolio-api/referrer"
+	"portfolio-api/repository"
+	"portfolio-api/stats"
+)
+
+// Settle is how long after the end of a UTC day its visits are rolled up,
+// leaving time for the day's last sessions to end
+const Settle = stats.SessionTimeout + 5*time.Minute
+
+// Job rolls finished UTC days of visits into rollups. Days are rolled up
+// whole because visitor IDs rotate at UTC midnight, so each day's visitors
+// and sessions are complete within it.
+type Job struct {
+	visits    repository.VisitRepository
+	rollups   repository.RollupRepository
+	referrers *referrer.Classifier
+	// retention is how long raw visits are kept; zero keeps them forever
+	retention time.Duration
+}
+
+// NewJob creates a Job that deletes raw visits older than retentionDays once
+// they are rolled up; retentionDays <= 0 keeps them forever
+func NewJob(visits repository.VisitRepository, rollups repository.RollupRepository, referrers *referrer.Classifier, retentionDays int) *Job {
+	job := &Job{visits: visits, rollups: rollups, referrers: referrers}
+	if retentionDays > 0 {
+		job.retention = time.Duration(retentionDays) * 24 * time.Hour
+	}
+	return job
+}
+
+// Run rolls up immediately and then every interval until ctx is done
+func (j *Job) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("Warning: analytics rollup failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce rolls up every day that has settled by now and was not rolled up
+// yet, then deletes raw visits past the retention period
+func (j *Job) RunOnce(ctx context.Context, now time.Time) error {
+	day, err := j.rollups.Watermark(ctx)
+	if err != nil {
+		return err
+	}
+	if day.IsZero() {
+		oldest, err := j.visits.Oldest(ctx)
+		if err != nil || oldest.IsZero() {
+			return err
+		}
+		day = stats.Day.Truncate(oldest.UTC())
+	}
+
+	for ; !day.AddDate(0, 0, 1).Add(Settle).After(now); day = day.AddDate(0, 0, 1) {
+		if err := j.rollUpDay(ctx, day); err != nil {
+			return err
+		}
+	}
+
+	return j.applyRetention(ctx, now)
+}
+
+// rollUpDay replaces the rollups of the UTC day starting at day with ones
+// computed from its visits
+func (j *Job) rollUpDay(ctx context.Context, day time.Time) error {
+	visits, err := j.visits.List(ctx, repository.VisitFilter{From: day, To: day.AddDate(0, 0, 1)})
+	if err != nil {
+		return err
+	}
+
+	hourly := stats.RollupVisits(visits, j.referrers)
+	daily := stats.DailyRollups(hourly)
+
+	// The latest daily total marks the watermark, so record days without
+	// human visits too
+	hasTotal := false
+	for _, row := range daily {
+		if row.Dimension == stats.DimTotal && !row.IsBot {
+			hasTotal = true
+			break
+		}
+	}
+	if !hasTotal {
+		daily = append(daily, models.Rollup{PeriodStart: day, Dimension: stats.DimTotal})
+	}
+
+	return j.rollups.ReplaceDay(ctx, day, hourly, daily)
+}
+
+// applyRetention deletes raw visits older than the retention period that
+// have been rolled up
+func (j *Job) applyRetention(ctx context.Context, now time.Time) error {
+	if j.retention == 0 {
+		return nil
+	}
+
+	watermark, err := j.rollups.Watermark(ctx)
+	if err != nil || watermark.IsZero() {
+		return err
+	}
+
+	cutoff := now.Add(-j.retention)
+	if cutoff.After(watermark) {
+		cutoff = watermark
+	}
+
+	deleted, err := j.visits.DeleteBefore(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d raw visits recorded before %s", deleted, cutoff.Format(time.RFC3339))
+	}
+	return nil
+}
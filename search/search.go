@@ -0,0 +1,185 @@
+// Package search tokenizes portfolio text, keeps an inverted index of it and
+// cuts highlighted snippets out of matching documents. Postgres ranks with
+// its own tsvector columns; the index serves the other backends.
+package search
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Field weights, matching the defaults of Postgres' ts_rank for weights A, B and C
+const (
+	WeightA = 1.0
+	WeightB = 0.4
+	WeightC = 0.2
+)
+
+// Field is a piece of a document's text and how much a match in it counts
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Document is a searchable record. Fields run from the most to the least
+// important; the last ones hold the longer prose that snippets prefer.
+type Document struct {
+	Type  string
+	ID    string
+	Title string
+	// Owner is the ID of the user the document belongs to, if any
+	Owner  string
+	Fields []Field
+}
+
+// Snippet highlights the terms in the last field that contains one, falling
+// back to the start of the last non-empty field
+func (d Document) Snippet(terms []string) string {
+	fallback := ""
+	for i := len(d.Fields) - 1; i >= 0; i-- {
+		text := d.Fields[i].Text
+		if text == "" {
+			continue
+		}
+		if snippet, ok := Highlight(text, terms); ok {
+			return snippet
+		}
+		if fallback == "" {
+			fallback = text
+		}
+	}
+	snippet, _ := Highlight(fallback, terms)
+	return snippet
+}
+
+// Hit is a document matching every term of a query
+type Hit struct {
+	Document
+	Rank float64
+}
+
+type key struct {
+	docType string
+	id      string
+}
+
+// Index is an inverted index from tokens to the documents containing them,
+// safe for concurrent use
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[key]Document
+	postings map[string]map[key]float64
+	// tokens lists the keys of postings in order, for prefix lookups
+	tokens []string
+}
+
+// NewIndex returns an empty index
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[key]Document),
+		postings: make(map[string]map[key]float64),
+	}
+}
+
+// Put adds a document, replacing any with the same type and ID
+func (x *Index) Put(doc Document) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	k := key{docType: doc.Type, id: doc.ID}
+	x.remove(k)
+	x.docs[k] = doc
+	for _, field := range doc.Fields {
+		for _, token := range Tokens(field.Text) {
+			docs, ok := x.postings[token]
+			if !ok {
+				docs = make(map[key]float64)
+				x.postings[token] = docs
+				i := sort.SearchStrings(x.tokens, token)
+				x.tokens = append(x.tokens, "")
+				copy(x.tokens[i+1:], x.tokens[i:])
+				x.tokens[i] = token
+			}
+			docs[k] += field.Weight
+		}
+	}
+}
+
+// Remove drops a document; removing an unknown document does nothing
+func (x *Index) Remove(docType, id string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(key{docType: docType, id: id})
+}
+
+// remove drops a document; callers hold the write lock
+func (x *Index) remove(k key) {
+	doc, ok := x.docs[k]
+	if !ok {
+		return
+	}
+	delete(x.docs, k)
+	for _, field := range doc.Fields {
+		for _, token := range Tokens(field.Text) {
+			docs, ok := x.postings[token]
+			if !ok {
+				continue
+			}
+			delete(docs, k)
+			if len(docs) == 0 {
+				delete(x.postings, token)
+				i := sort.SearchStrings(x.tokens, token)
+				x.tokens = append(x.tokens[:i], x.tokens[i+1:]...)
+			}
+		}
+	}
+}
+
+// Search returns the documents of the given types (any type when empty)
+// with a token starting with each term, best first. A document's rank adds
+// up the weights of the fields its matching tokens appear in.
+func (x *Index) Search(terms []string, types []string) []Hit {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var ranks map[key]float64
+	for _, term := range terms {
+		termRanks := make(map[key]float64)
+		for i := sort.SearchStrings(x.tokens, term); i < len(x.tokens) && strings.HasPrefix(x.tokens[i], term); i++ {
+			for k, weight := range x.postings[x.tokens[i]] {
+				if len(types) == 0 || slices.Contains(types, k.docType) {
+					termRanks[k] += weight
+				}
+			}
+		}
+
+		if ranks == nil {
+			ranks = termRanks
+			continue
+		}
+		for k := range ranks {
+			if rank, ok := termRanks[k]; ok {
+				ranks[k] += rank
+			} else {
+				delete(ranks, k)
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(ranks))
+	for k, rank := range ranks {
+		hits = append(hits, Hit{Document: x.docs[k], Rank: rank})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		if hits[i].Type != hits[j].Type {
+			return hits[i].Type < hits[j].Type
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	return hits
+}
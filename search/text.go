@@ -0,0 +1,136 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxQueryLength bounds the length of a search query in bytes
+	MaxQueryLength = 200
+	// MaxTerms bounds the number of distinct terms in a search query
+	MaxTerms = 8
+	// snippetWords is the length of a snippet in words
+	snippetWords = 24
+	// snippetLead is how many words a snippet shows before the first match
+	snippetLead = 5
+)
+
+// Marks wrapped around matched words in snippets
+const (
+	MarkStart = "<mark>"
+	MarkEnd   = "</mark>"
+)
+
+type word struct {
+	start, end int
+	token      string
+}
+
+// words splits text into runs of letters and digits, like the "simple"
+// Postgres text search configuration
+func words(text string) []word {
+	var result []word
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			result = append(result, word{start: start, end: i, token: strings.ToLower(text[start:i])})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		result = append(result, word{start: start, end: len(text), token: strings.ToLower(text[start:])})
+	}
+	return result
+}
+
+// Tokens returns the lowercased words of text
+func Tokens(text string) []string {
+	ws := words(text)
+	tokens := make([]string, len(ws))
+	for i, w := range ws {
+		tokens[i] = w.token
+	}
+	return tokens
+}
+
+// Terms returns the distinct tokens of a query, at most MaxTerms of them
+func Terms(query string) []string {
+	terms := []string{}
+	seen := make(map[string]bool)
+	for _, token := range Tokens(query) {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		terms = append(terms, token)
+		if len(terms) == MaxTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// matches reports whether a token starts with one of the terms
+func matches(token string, terms []string) bool {
+	for _, term := range terms {
+		if strings.HasPrefix(token, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// Highlight cuts a snippet of text around its first word starting with one
+// of the terms, HTML-escaped with matching words wrapped in MarkStart and
+// MarkEnd. Without a match the snippet is the start of text and ok is false.
+func Highlight(text string, terms []string) (snippet string, ok bool) {
+	ws := words(text)
+	if len(ws) == 0 {
+		return html.EscapeString(strings.TrimSpace(text)), false
+	}
+
+	first := -1
+	for i, w := range ws {
+		if matches(w.token, terms) {
+			first = i
+			break
+		}
+	}
+
+	from := 0
+	if first > snippetLead {
+		from = first - snippetLead
+	}
+	to := min(len(ws), from+snippetWords)
+	// Keep the snippet full length near the end of the text
+	from = max(0, min(from, to-snippetWords))
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("… ")
+	}
+	pos := ws[from].start
+	for _, w := range ws[from:to] {
+		b.WriteString(html.EscapeString(text[pos:w.start]))
+		if matches(w.token, terms) {
+			b.WriteString(MarkStart + html.EscapeString(text[w.start:w.end]) + MarkEnd)
+		} else {
+			b.WriteString(html.EscapeString(text[w.start:w.end]))
+		}
+		pos = w.end
+	}
+	if to < len(ws) {
+		b.WriteString(" …")
+	} else {
+		b.WriteString(html.EscapeString(strings.TrimRightFunc(text[pos:], unicode.IsSpace)))
+	}
+	return b.String(), first >= 0
+}
@@ -0,0 +1,112 @@
+// Package slug derives URL slugs from titles, romanizing Korean with the
+// Revised Romanization of Korean syllable by syllable
+package slug
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// MaxLength bounds the length of a slug in bytes
+const MaxLength = 80
+
+// Fallback is the slug of a title without a letter or digit to keep
+const Fallback = "project"
+
+var pattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// Valid reports whether s can be a slug: lowercase ASCII letters and digits
+// in hyphen separated words, and not a UUID, which would read as an ID
+func Valid(s string) bool {
+	if len(s) > MaxLength || !pattern.MatchString(s) {
+		return false
+	}
+	_, err := uuid.Parse(s)
+	return err != nil
+}
+
+// Make derives a slug from a title. Hangul syllables are romanized, accented
+// Latin letters lose their accents and anything else separates words.
+func Make(title string) string {
+	var b strings.Builder
+	separate := false
+	for _, r := range strings.ToLower(title) {
+		var word string
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word = string(r)
+		case r >= hangulFirst && r <= hangulLast:
+			word = romanize(r)
+		default:
+			word = latin[r]
+		}
+		if word == "" {
+			separate = b.Len() > 0
+			continue
+		}
+		if separate {
+			b.WriteByte('-')
+			separate = false
+		}
+		b.WriteString(word)
+	}
+
+	s := b.String()
+	if len(s) > MaxLength {
+		s = s[:MaxLength]
+		// Cut at a word boundary when there is one
+		if i := strings.LastIndexByte(s, '-'); i > 0 {
+			s = s[:i]
+		}
+	}
+	if !Valid(s) {
+		return Fallback
+	}
+	return s
+}
+
+// Unique returns base, or base with the smallest numeric suffix from 2 up,
+// that taken does not contain
+func Unique(base string, taken map[string]bool) string {
+	if !taken[base] {
+		return base
+	}
+	for n := 2; ; n++ {
+		suffix := "-" + strconv.Itoa(n)
+		candidate := strings.TrimRight(base[:min(len(base), MaxLength-len(suffix))], "-") + suffix
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// Hangul syllables are composed as 0xAC00 + (initial*21 + medial)*28 + final
+const (
+	hangulFirst = 0xAC00
+	hangulLast  = 0xD7A3
+)
+
+var (
+	initials = []string{"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s", "ss", "", "j", "jj", "ch", "k", "t", "p", "h"}
+	medials  = []string{"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i"}
+	finals   = []string{"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "l", "l", "l", "p", "l", "m", "p", "p", "t", "t", "ng", "t", "t", "k", "t", "p", "t"}
+)
+
+// romanize spells a Hangul syllable in Latin letters
+func romanize(r rune) string {
+	i := int(r - hangulFirst)
+	return initials[i/(21*28)] + medials[i/28%21] + finals[i%28]
+}
+
+// latin maps accented Latin letters to their plain spelling
+var latin = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ý': "y", 'ÿ': "y", 'ß': "ss",
+}
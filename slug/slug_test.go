@@ -0,0 +1,70 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{name: "words", title: "Hello, World!", want: "hello-world"},
+		{name: "digits", title: "Portfolio API v2", want: "portfolio-api-v2"},
+		{name: "Hangul", title: "안녕하세요", want: "annyeonghaseyo"},
+		{name: "Hangul and Latin", title: "서울 Project", want: "seoul-project"},
+		{name: "accents", title: "Café Crème", want: "cafe-creme"},
+		{name: "sharp s", title: "Straße 2024", want: "strasse-2024"},
+		{name: "no letters", title: " -- !! ", want: Fallback},
+		{name: "unsupported script", title: "日本語", want: Fallback},
+		{name: "UUID", title: "123e4567-e89b-12d3-a456-426614174000", want: Fallback},
+		{name: "cut at a word boundary", title: strings.Repeat("word ", 30), want: strings.Repeat("word-", 15) + "word"},
+		{name: "cut inside one long word", title: strings.Repeat("a", 100), want: strings.Repeat("a", MaxLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Make(tt.title)
+			if got != tt.want {
+				t.Errorf("Make(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+			if !Valid(got) {
+				t.Errorf("Make(%q) = %q is not a valid slug", tt.title, got)
+			}
+		})
+	}
+}
+
+func TestUnique(t *testing.T) {
+	long := strings.Repeat("ab-", 26) + "ab"
+
+	tests := []struct {
+		name  string
+		base  string
+		taken []string
+		want  string
+	}{
+		{name: "free", base: "portfolio", taken: []string{"other"}, want: "portfolio"},
+		{name: "taken", base: "portfolio", taken: []string{"portfolio"}, want: "portfolio-2"},
+		{name: "skips taken suffixes", base: "portfolio", taken: []string{"portfolio", "portfolio-2", "portfolio-3"}, want: "portfolio-4"},
+		{name: "stays within the length limit", base: long, taken: []string{long}, want: long[:77] + "-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taken := make(map[string]bool)
+			for _, s := range tt.taken {
+				taken[s] = true
+			}
+			got := Unique(tt.base, taken)
+			if got != tt.want {
+				t.Errorf("Unique(%q) = %q, want %q", tt.base, got, tt.want)
+			}
+			if !Valid(got) {
+				t.Errorf("Unique(%q) = %q is not a valid slug", tt.base, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package spam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CaptchaVerifier checks a CAPTCHA response token with its provider
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) (bool, error)
+}
+
+// siteVerifyURLs are the verification endpoints of the supported providers,
+// which all share the reCAPTCHA siteverify protocol
+var siteVerifyURLs = map[string]string{
+	"turnstile": "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+	"hcaptcha":  "https://api.hcaptcha.com/siteverify",
+	"recaptcha": "https://www.google.com/recaptcha/api/siteverify",
+}
+
+// SiteVerifier verifies tokens against a siteverify-compatible endpoint
+type SiteVerifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewCaptchaVerifier returns a verifier for provider: turnstile, hcaptcha or recaptcha
+func NewCaptchaVerifier(provider, secret string) (*SiteVerifier, error) {
+	endpoint, ok := siteVerifyURLs[strings.ToLower(provider)]
+	if !ok {
+		return nil, fmt.Errorf("unknown CAPTCHA provider %q", provider)
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("CAPTCHA provider %s requires a secret", provider)
+	}
+	return &SiteVerifier{
+		url:    endpoint,
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Verify posts the token to the provider and reports whether it was accepted
+func (v *SiteVerifier) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	form := url.Values{"secret": {v.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("CAPTCHA verification returned %s", resp.Status)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+	return result.Success, nil
+}
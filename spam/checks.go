@@ -0,0 +1,127 @@
+package spam
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Scores added by the built-in checks; the default threshold is 50
+const (
+	scoreHoneypot     = 100
+	scoreTooFast      = 60
+	scoreBadToken     = 30
+	scoreMissingToken = 10
+	scorePerLink      = 15
+	scorePerKeyword   = 25
+)
+
+// HoneypotCheck flags submissions that fill in the hidden honeypot field
+type HoneypotCheck struct{}
+
+func (HoneypotCheck) Check(ctx context.Context, sub *Submission, verdict *Verdict) error {
+	if strings.TrimSpace(sub.Honeypot) != "" {
+		verdict.Add(scoreHoneypot, "honeypot")
+	}
+	return nil
+}
+
+// TimingCheck flags forms submitted sooner than MinDelay after their token
+// was issued. Missing tokens score lightly so older clients keep working.
+type TimingCheck struct {
+	Tokens   *TokenIssuer
+	MinDelay time.Duration
+}
+
+func (t TimingCheck) Check(ctx context.Context, sub *Submission, verdict *Verdict) error {
+	if sub.FormToken == "" {
+		verdict.Add(scoreMissingToken, "missing_form_token")
+		return nil
+	}
+
+	issuedAt, err := t.Tokens.Parse(sub.FormToken)
+	elapsed := sub.ReceivedAt.Sub(issuedAt)
+	switch {
+	case err != nil || elapsed < 0 || elapsed > tokenMaxAge:
+		verdict.Add(scoreBadToken, "invalid_form_token")
+	case elapsed < t.MinDelay:
+		verdict.Add(scoreTooFast, "submitted_too_fast")
+	}
+	return nil
+}
+
+// RateLimitCheck rejects senders that submit too often, by IP and by email.
+// It goes after CaptchaCheck so that failed CAPTCHAs do not use up the
+// quota of the email address they name.
+type RateLimitCheck struct {
+	PerIP    *RateLimiter
+	PerEmail *RateLimiter
+}
+
+func (r RateLimitCheck) Check(ctx context.Context, sub *Submission, verdict *Verdict) error {
+	if ok, retryAfter := r.PerIP.Allow(sub.IP, sub.ReceivedAt); !ok {
+		return &RateLimitError{RetryAfter: retryAfter}
+	}
+	if ok, retryAfter := r.PerEmail.Allow(strings.ToLower(sub.Email), sub.ReceivedAt); !ok {
+		return &RateLimitError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// linkPattern matches URLs and bare www. links
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// LinkCheck scores every link beyond MaxLinks in the name, subject and message
+type LinkCheck struct {
+	MaxLinks int
+}
+
+func (l LinkCheck) Check(ctx context.Context, sub *Submission, verdict *Verdict) error {
+	links := len(linkPattern.FindAllString(sub.Name+"\n"+sub.Subject+"\n"+sub.Message, -1))
+	if extra := links - l.MaxLinks; extra > 0 {
+		verdict.Add(extra*scorePerLink, "too_many_links")
+	}
+	return nil
+}
+
+// DefaultKeywords are phrases common in contact form spam
+var DefaultKeywords = []string{
+	"viagra", "casino", "crypto investment", "bitcoin", "forex", "seo services",
+	"backlinks", "guest post", "loan offer", "weight loss", "porn", "escort",
+}
+
+// KeywordCheck scores each listed keyword found in the submission
+type KeywordCheck struct {
+	Keywords []string
+}
+
+func (k KeywordCheck) Check(ctx context.Context, sub *Submission, verdict *Verdict) error {
+	text := strings.ToLower(sub.Name + "\n" + sub.Subject + "\n" + sub.Message)
+	for _, keyword := range k.Keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" && strings.Contains(text, keyword) {
+			verdict.Add(scorePerKeyword, "keyword:"+keyword)
+		}
+	}
+	return nil
+}
+
+// CaptchaCheck requires a valid CAPTCHA response
+type CaptchaCheck struct {
+	Verifier CaptchaVerifier
+}
+
+func (c CaptchaCheck) Check(ctx context.Context, sub *Submission, verdict *Verdict) error {
+	if sub.CaptchaToken == "" {
+		return ErrCaptchaFailed
+	}
+	ok, err := c.Verifier.Verify(ctx, sub.CaptchaToken, sub.IP)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCaptchaFailed
+	}
+	return nil
+}
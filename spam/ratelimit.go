@@ -0,0 +1,68 @@
+package spam
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter allows at most limit events per key within a sliding window.
+// State is kept in memory, so each API instance limits independently.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	events    map[string][]time.Time
+	lastSweep time.Time
+}
+
+// NewRateLimiter creates a limiter; a limit of zero or less disables it
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{limit: limit, window: window, events: make(map[string][]time.Time)}
+}
+
+// Allow records an event for key at now. When the key is over its limit it
+// returns false and how long until the oldest event leaves the window.
+func (r *RateLimiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	if r.limit <= 0 || key == "" {
+		return true, 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now.Sub(r.lastSweep) > r.window {
+		r.sweep(now)
+	}
+
+	events := r.recent(r.events[key], now)
+	if len(events) >= r.limit {
+		r.events[key] = events
+		return false, events[0].Add(r.window).Sub(now)
+	}
+
+	r.events[key] = append(events, now)
+	return true, 0
+}
+
+// recent drops the events that have left the window
+func (r *RateLimiter) recent(events []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-r.window)
+	i := 0
+	for i < len(events) && !events[i].After(cutoff) {
+		i++
+	}
+	return events[i:]
+}
+
+// sweep forgets keys with no events inside the window
+func (r *RateLimiter) sweep(now time.Time) {
+	for key, events := range r.events {
+		if events = r.recent(events, now); len(events) == 0 {
+			delete(r.events, key)
+		} else {
+			r.events[key] = events
+		}
+	}
+	r.lastSweep = now
+}
@@ -0,0 +1,90 @@
+// Package spam scores contact form submissions and rejects abusive senders
+package spam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCaptchaFailed is returned when a CAPTCHA is configured and the
+// submission's token does not verify
+var ErrCaptchaFailed = errors.New("CAPTCHA verification failed")
+
+// RateLimitError is returned when a sender has submitted too often
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("too many submissions, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+// Submission is what the checks inspect
+type Submission struct {
+	Name         string
+	Email        string
+	Subject      string
+	Message      string
+	Honeypot     string
+	FormToken    string
+	CaptchaToken string
+	IP           string
+	UserAgent    string
+	ReceivedAt   time.Time
+}
+
+// Verdict accumulates the score and reasons given by each check
+type Verdict struct {
+	Score   int
+	Reasons []string
+	// Spam is set by the Filter once the score reaches its threshold
+	Spam bool
+}
+
+// Add raises the score by points and records why
+func (v *Verdict) Add(points int, reason string) {
+	v.Score += points
+	v.Reasons = append(v.Reasons, reason)
+}
+
+// Check inspects a submission. It scores suspicious content through the
+// Verdict and returns an error to reject the submission outright.
+type Check interface {
+	Check(ctx context.Context, sub *Submission, verdict *Verdict) error
+}
+
+// Filter runs a submission through its checks in order
+type Filter struct {
+	threshold int
+	tokens    *TokenIssuer
+	checks    []Check
+}
+
+// NewFilter creates a Filter marking submissions scoring at least threshold as
+// spam. tokens may be nil when form tokens are not used.
+func NewFilter(threshold int, tokens *TokenIssuer, checks ...Check) *Filter {
+	return &Filter{threshold: threshold, tokens: tokens, checks: checks}
+}
+
+// Evaluate scores sub, stopping at the first check that rejects it
+func (f *Filter) Evaluate(ctx context.Context, sub *Submission) (Verdict, error) {
+	verdict := Verdict{Reasons: []string{}}
+	for _, check := range f.checks {
+		if err := check.Check(ctx, sub, &verdict); err != nil {
+			return verdict, err
+		}
+	}
+	verdict.Spam = verdict.Score >= f.threshold
+	return verdict, nil
+}
+
+// IssueToken returns a form token for the time-to-submit check, or "" when
+// tokens are not used
+func (f *Filter) IssueToken() string {
+	if f.tokens == nil {
+		return ""
+	}
+	return f.tokens.Issue(time.Now())
+}
@@ -0,0 +1,255 @@
+package spam
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// stubVerifier accepts one CAPTCHA token
+type stubVerifier struct {
+	valid string
+	err   error
+}
+
+func (v stubVerifier) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	return token == v.valid, v.err
+}
+
+func TestChecks(t *testing.T) {
+	tokens, err := NewTokenIssuer("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	timing := TimingCheck{Tokens: tokens, MinDelay: 3 * time.Second}
+	otherTokens, _ := NewTokenIssuer("other secret")
+
+	tests := []struct {
+		name        string
+		check       Check
+		sub         Submission
+		wantScore   int
+		wantReasons []string
+		wantErr     error
+	}{
+		{
+			name:  "empty honeypot",
+			check: HoneypotCheck{},
+			sub:   Submission{Honeypot: "  "},
+		},
+		{
+			name:        "filled honeypot",
+			check:       HoneypotCheck{},
+			sub:         Submission{Honeypot: "http://spam.example"},
+			wantScore:   scoreHoneypot,
+			wantReasons: []string{"honeypot"},
+		},
+		{
+			name:        "missing form token",
+			check:       timing,
+			sub:         Submission{ReceivedAt: now},
+			wantScore:   scoreMissingToken,
+			wantReasons: []string{"missing_form_token"},
+		},
+		{
+			name:  "form submitted after the delay",
+			check: timing,
+			sub:   Submission{FormToken: tokens.Issue(now.Add(-time.Minute)), ReceivedAt: now},
+		},
+		{
+			name:        "form submitted too fast",
+			check:       timing,
+			sub:         Submission{FormToken: tokens.Issue(now.Add(-time.Second)), ReceivedAt: now},
+			wantScore:   scoreTooFast,
+			wantReasons: []string{"submitted_too_fast"},
+		},
+		{
+			name:        "expired form token",
+			check:       timing,
+			sub:         Submission{FormToken: tokens.Issue(now.Add(-tokenMaxAge - time.Minute)), ReceivedAt: now},
+			wantScore:   scoreBadToken,
+			wantReasons: []string{"invalid_form_token"},
+		},
+		{
+			name:        "form token from the future",
+			check:       timing,
+			sub:         Submission{FormToken: tokens.Issue(now.Add(time.Hour)), ReceivedAt: now},
+			wantScore:   scoreBadToken,
+			wantReasons: []string{"invalid_form_token"},
+		},
+		{
+			name:        "form token signed with another secret",
+			check:       timing,
+			sub:         Submission{FormToken: otherTokens.Issue(now.Add(-time.Minute)), ReceivedAt: now},
+			wantScore:   scoreBadToken,
+			wantReasons: []string{"invalid_form_token"},
+		},
+		{
+			name:  "links within the limit",
+			check: LinkCheck{MaxLinks: 2},
+			sub:   Submission{Message: "See https://a.example and www.b.example"},
+		},
+		{
+			name:        "links beyond the limit",
+			check:       LinkCheck{MaxLinks: 1},
+			sub:         Submission{Subject: "http://a.example", Message: "https://b.example www.c.example"},
+			wantScore:   2 * scorePerLink,
+			wantReasons: []string{"too_many_links"},
+		},
+		{
+			name:        "links in the name",
+			check:       LinkCheck{MaxLinks: 0},
+			sub:         Submission{Name: "Prizes at https://a.example"},
+			wantScore:   scorePerLink,
+			wantReasons: []string{"too_many_links"},
+		},
+		{
+			name:        "keywords in any case",
+			check:       KeywordCheck{Keywords: DefaultKeywords},
+			sub:         Submission{Subject: "Cheap SEO Services", Message: "and a Casino"},
+			wantScore:   2 * scorePerKeyword,
+			wantReasons: []string{"keyword:casino", "keyword:seo services"},
+		},
+		{
+			name:  "blank keywords are ignored",
+			check: KeywordCheck{Keywords: []string{" ", ""}},
+			sub:   Submission{Message: "Hello"},
+		},
+		{
+			name:    "missing CAPTCHA",
+			check:   CaptchaCheck{Verifier: stubVerifier{valid: "ok"}},
+			sub:     Submission{},
+			wantErr: ErrCaptchaFailed,
+		},
+		{
+			name:    "rejected CAPTCHA",
+			check:   CaptchaCheck{Verifier: stubVerifier{valid: "ok"}},
+			sub:     Submission{CaptchaToken: "bad"},
+			wantErr: ErrCaptchaFailed,
+		},
+		{
+			name:  "accepted CAPTCHA",
+			check: CaptchaCheck{Verifier: stubVerifier{valid: "ok"}},
+			sub:   Submission{CaptchaToken: "ok"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := Verdict{Reasons: []string{}}
+			err := tt.check.Check(context.Background(), &tt.sub, &verdict)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if verdict.Score != tt.wantScore {
+				t.Errorf("score = %d, want %d", verdict.Score, tt.wantScore)
+			}
+			if tt.wantReasons == nil {
+				tt.wantReasons = []string{}
+			}
+			if !reflect.DeepEqual(verdict.Reasons, tt.wantReasons) {
+				t.Errorf("reasons = %v, want %v", verdict.Reasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestRateLimitCheck(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	check := RateLimitCheck{
+		PerIP:    NewRateLimiter(2, time.Hour),
+		PerEmail: NewRateLimiter(1, time.Hour),
+	}
+
+	tests := []struct {
+		name      string
+		sub       Submission
+		wantLimit bool
+	}{
+		{name: "first submission", sub: Submission{IP: "203.0.113.1", Email: "a@example.com", ReceivedAt: now}},
+		{name: "same email in another case", sub: Submission{IP: "203.0.113.2", Email: "A@Example.com", ReceivedAt: now}, wantLimit: true},
+		{name: "same IP, new email", sub: Submission{IP: "203.0.113.1", Email: "b@example.com", ReceivedAt: now}},
+		{name: "IP over its limit", sub: Submission{IP: "203.0.113.1", Email: "c@example.com", ReceivedAt: now}, wantLimit: true},
+		{name: "after the window", sub: Submission{IP: "203.0.113.1", Email: "a@example.com", ReceivedAt: now.Add(time.Hour + time.Second)}},
+	}
+
+	for _, tt := range tests {
+		err := check.Check(context.Background(), &tt.sub, &Verdict{})
+		var limited *RateLimitError
+		if got := errors.As(err, &limited); got != tt.wantLimit {
+			t.Errorf("%s: rate limited = %v, want %v (err %v)", tt.name, got, tt.wantLimit, err)
+		}
+		if limited != nil && limited.RetryAfter <= 0 {
+			t.Errorf("%s: RetryAfter = %s, want a positive wait", tt.name, limited.RetryAfter)
+		}
+	}
+}
+
+func TestFailedCaptchaKeepsTheRateLimit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	// Ordered as main.go orders them
+	filter := NewFilter(50, nil,
+		CaptchaCheck{Verifier: stubVerifier{valid: "ok"}},
+		RateLimitCheck{PerIP: NewRateLimiter(10, time.Hour), PerEmail: NewRateLimiter(1, time.Hour)},
+	)
+
+	for i := 0; i < 3; i++ {
+		sub := Submission{IP: "203.0.113.9", Email: "victim@example.com", CaptchaToken: "wrong", ReceivedAt: now}
+		if _, err := filter.Evaluate(context.Background(), &sub); !errors.Is(err, ErrCaptchaFailed) {
+			t.Fatalf("failed CAPTCHA %d: got %v, want ErrCaptchaFailed", i, err)
+		}
+	}
+
+	sub := Submission{IP: "203.0.113.1", Email: "victim@example.com", CaptchaToken: "ok", ReceivedAt: now}
+	if _, err := filter.Evaluate(context.Background(), &sub); err != nil {
+		t.Errorf("first verified submission: %v, want it within the email's limit", err)
+	}
+}
+
+func TestFilterEvaluate(t *testing.T) {
+	filter := NewFilter(50, nil, HoneypotCheck{}, KeywordCheck{Keywords: []string{"casino"}})
+
+	tests := []struct {
+		name      string
+		sub       Submission
+		wantScore int
+		wantSpam  bool
+	}{
+		{name: "clean", sub: Submission{Message: "Hello"}},
+		{name: "below the threshold", sub: Submission{Message: "casino night"}, wantScore: scorePerKeyword},
+		{name: "over the threshold", sub: Submission{Honeypot: "x"}, wantScore: scoreHoneypot, wantSpam: true},
+	}
+
+	for _, tt := range tests {
+		verdict, err := filter.Evaluate(context.Background(), &tt.sub)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if verdict.Score != tt.wantScore || verdict.Spam != tt.wantSpam {
+			t.Errorf("%s: score %d spam %v, want %d %v", tt.name, verdict.Score, verdict.Spam, tt.wantScore, tt.wantSpam)
+		}
+	}
+
+	if token := filter.IssueToken(); token != "" {
+		t.Errorf("IssueToken without an issuer = %q, want empty", token)
+	}
+}
+
+func TestTokenIssuerParse(t *testing.T) {
+	tokens, _ := NewTokenIssuer("secret")
+	issuedAt := time.Unix(1704110400, 0)
+
+	got, err := tokens.Parse(tokens.Issue(issuedAt))
+	if err != nil || !got.Equal(issuedAt) {
+		t.Fatalf("Parse = %v, %v; want %v", got, err, issuedAt)
+	}
+
+	for _, token := range []string{"", "1704110400", "1704110400.forged", "not-a-time." + tokens.sign("not-a-time")} {
+		if _, err := tokens.Parse(token); err == nil {
+			t.Errorf("Parse(%q) accepted an invalid token", token)
+		}
+	}
+}
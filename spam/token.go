@@ -0,0 +1,61 @@
+package spam
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tokenMaxAge bounds how long a form may stay open before its token expires
+const tokenMaxAge = 24 * time.Hour
+
+var errInvalidToken = errors.New("invalid form token")
+
+// TokenIssuer signs the time a contact form was rendered so the submission
+// can prove it was not sent instantly
+type TokenIssuer struct {
+	secret []byte
+}
+
+// NewTokenIssuer creates an issuer. With an empty secret a random one is
+// generated, which invalidates outstanding tokens on restart.
+func NewTokenIssuer(secret string) (*TokenIssuer, error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+	}
+	return &TokenIssuer{secret: key}, nil
+}
+
+// Issue returns a token for a form rendered at issuedAt
+func (t *TokenIssuer) Issue(issuedAt time.Time) string {
+	payload := strconv.FormatInt(issuedAt.Unix(), 10)
+	return payload + "." + t.sign(payload)
+}
+
+// Parse verifies token and returns when it was issued
+func (t *TokenIssuer) Parse(token string) (time.Time, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(t.sign(payload))) {
+		return time.Time{}, errInvalidToken
+	}
+	seconds, err := strconv.ParseInt(payload, 10, 64)
+	if err != nil {
+		return time.Time{}, errInvalidToken
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+func (t *TokenIssuer) sign(payload string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
@@ -0,0 +1,72 @@
+// Package stats aggregates recorded visits into the analytics responses
+package stats
+
+import (
+	"fmt"
+	"time"
+)
+
+// Granularity is the bucket size of a time series
+type Granularity string
+
+const (
+	Hour  Granularity = "hour"
+	Day   Granularity = "day"
+	Week  Granularity = "week"
+	Month Granularity = "month"
+)
+
+// ParseGranularity validates a granularity query value; empty means Day
+func ParseGranularity(value string) (Granularity, error) {
+	switch g := Granularity(value); g {
+	case "":
+		return Day, nil
+	case Hour, Day, Week, Month:
+		return g, nil
+	default:
+		return "", fmt.Errorf("invalid granularity %q: use hour, day, week or month", value)
+	}
+}
+
+// Truncate returns the start of the bucket containing t, in t's location.
+// Weeks start on Monday.
+func (g Granularity) Truncate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	switch g {
+	case Hour:
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, t.Location())
+	case Week:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	case Month:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// Next returns the start of the bucket after the one starting at start
+func (g Granularity) Next(start time.Time) time.Time {
+	switch g {
+	case Hour:
+		return start.Add(time.Hour)
+	case Week:
+		return start.AddDate(0, 0, 7)
+	case Month:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// Label formats a bucket start for the TimeStat.Date field
+func (g Granularity) Label(start time.Time) string {
+	switch g {
+	case Hour:
+		return start.Format("2006-01-02T15:00")
+	case Month:
+		return start.Format("2006-01")
+	default:
+		return start.Format("2006-01-02")
+	}
+}
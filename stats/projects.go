@@ -0,0 +1,36 @@
+package stats
+
+import "portfolio-api/models"
+
+// ComputeProjectEngagement sums the project rollups into views and visitors
+// per project and adds the clicks, given per project ID and target
+func ComputeProjectEngagement(rows []models.Rollup, clicks map[string]map[string]int) map[string]models.ProjectEngagement {
+	engagement := make(map[string]models.ProjectEngagement)
+	for _, row := range rows {
+		if row.Dimension != DimProject {
+			continue
+		}
+		e := engagement[row.Value]
+		e.ProjectID = row.Value
+		e.Views += row.Views
+		e.Visitors += row.NewVisitors
+		engagement[row.Value] = e
+	}
+
+	for projectID, targets := range clicks {
+		e := engagement[projectID]
+		e.ProjectID = projectID
+		e.LiveClicks += targets[models.ClickTargetLive]
+		e.GithubClicks += targets[models.ClickTargetGithub]
+		engagement[projectID] = e
+	}
+
+	for projectID, e := range engagement {
+		e.Clicks = e.LiveClicks + e.GithubClicks
+		if e.Views > 0 {
+			e.CTR = round1(float64(e.Clicks) / float64(e.Views) * 100)
+		}
+		engagement[projectID] = e
+	}
+	return engagement
+}
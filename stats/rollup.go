@@ -0,0 +1,194 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"portfolio-api/models"
+	"portfolio-api/referrer"
+)
+
+// Rollup dimensions. Composite values join their parts with a tab:
+// "city\tcountry", "host\tchannel" and "source\tmedium\tcampaign".
+const (
+	DimTotal     = "total"
+	DimPage      = "page"
+	DimEntryPage = "entry_page"
+	DimExitPage  = "exit_page"
+	DimCountry   = "country"
+	DimCity      = "city"
+	DimBrowser   = "browser"
+	DimOS        = "os"
+	DimDevice    = "device"
+	DimChannel   = "channel"
+	DimReferrer  = "referrer"
+	DimCampaign  = "campaign"
+	DimProject   = "project"
+)
+
+type rollupKey struct {
+	period    int64
+	isBot     bool
+	dimension string
+	value     string
+}
+
+type rollupAccumulator struct {
+	rows     map[rollupKey]*models.Rollup
+	visitors map[rollupKey]map[string]struct{}
+}
+
+func newRollupAccumulator() *rollupAccumulator {
+	return &rollupAccumulator{
+		rows:     make(map[rollupKey]*models.Rollup),
+		visitors: make(map[rollupKey]map[string]struct{}),
+	}
+}
+
+func (a *rollupAccumulator) row(period time.Time, isBot bool, dimension, value string) *models.Rollup {
+	key := rollupKey{period: period.Unix(), isBot: isBot, dimension: dimension, value: value}
+	row := a.rows[key]
+	if row == nil {
+		row = &models.Rollup{PeriodStart: period, IsBot: isBot, Dimension: dimension, Value: value}
+		a.rows[key] = row
+	}
+	return row
+}
+
+// sorted returns the rows ordered by period, bot flag, dimension and value
+func (a *rollupAccumulator) sorted() []models.Rollup {
+	rows := make([]models.Rollup, 0, len(a.rows))
+	for _, row := range a.rows {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if !a.PeriodStart.Equal(b.PeriodStart) {
+			return a.PeriodStart.Before(b.PeriodStart)
+		}
+		if a.IsBot != b.IsBot {
+			return !a.IsBot
+		}
+		if a.Dimension != b.Dimension {
+			return a.Dimension < b.Dimension
+		}
+		return a.Value < b.Value
+	})
+	return rows
+}
+
+// RollupVisits aggregates visits into hourly rollups. Visits should cover
+// whole UTC days: visitor IDs rotate daily, so each day's first sighting of
+// a visitor and its sessions are complete within the day.
+func RollupVisits(visits []models.Visit, classifier *referrer.Classifier) []models.Rollup {
+	visits = append([]models.Visit(nil), visits...)
+	sort.SliceStable(visits, func(i, j int) bool { return visits[i].CreatedAt.Before(visits[j].CreatedAt) })
+
+	acc := newRollupAccumulator()
+	seen := make(map[rollupKey]map[string]struct{})
+
+	for _, visit := range visits {
+		hour := visit.CreatedAt.UTC().Truncate(time.Hour)
+		day := Day.Truncate(visit.CreatedAt.UTC())
+		visitor := visitorKey(visit)
+
+		for _, dim := range visitDimensions(visit, classifier) {
+			row := acc.row(hour, visit.IsBot, dim[0], dim[1])
+			row.Views++
+
+			hourKey := rollupKey{period: hour.Unix(), isBot: visit.IsBot, dimension: dim[0], value: dim[1]}
+			if acc.visitors[hourKey] == nil {
+				acc.visitors[hourKey] = make(map[string]struct{})
+			}
+			if _, ok := acc.visitors[hourKey][visitor]; !ok {
+				acc.visitors[hourKey][visitor] = struct{}{}
+				row.Visitors++
+			}
+
+			dayKey := rollupKey{period: day.Unix(), isBot: visit.IsBot, dimension: dim[0], value: dim[1]}
+			if seen[dayKey] == nil {
+				seen[dayKey] = make(map[string]struct{})
+			}
+			if _, ok := seen[dayKey][visitor]; !ok {
+				seen[dayKey][visitor] = struct{}{}
+				row.NewVisitors++
+			}
+		}
+	}
+
+	// Sessions count in the hour they started
+	for _, session := range GroupSessions(visits) {
+		first := session.Visits[0]
+		hour := first.CreatedAt.UTC().Truncate(time.Hour)
+
+		total := acc.row(hour, first.IsBot, DimTotal, "")
+		total.Sessions++
+		total.SessionPages += len(session.Visits)
+		total.SessionSeconds += session.Duration().Seconds()
+		if session.Bounced() {
+			total.Bounces++
+		}
+		acc.row(hour, first.IsBot, DimEntryPage, session.EntryPage()).Sessions++
+		acc.row(hour, first.IsBot, DimExitPage, session.ExitPage()).Sessions++
+	}
+
+	return acc.sorted()
+}
+
+// DailyRollups sums hourly rollups into UTC days
+func DailyRollups(hourly []models.Rollup) []models.Rollup {
+	acc := newRollupAccumulator()
+	for _, h := range hourly {
+		row := acc.row(Day.Truncate(h.PeriodStart.UTC()), h.IsBot, h.Dimension, h.Value)
+		row.Views += h.Views
+		// Visitors are first seen once per day, so the hourly counts add up
+		row.Visitors += h.NewVisitors
+		row.NewVisitors += h.NewVisitors
+		row.Sessions += h.Sessions
+		row.Bounces += h.Bounces
+		row.SessionPages += h.SessionPages
+		row.SessionSeconds += h.SessionSeconds
+	}
+	return acc.sorted()
+}
+
+// visitDimensions lists the dimension values a visit counts towards
+func visitDimensions(visit models.Visit, classifier *referrer.Classifier) [][2]string {
+	country := visit.Country
+	if country == "" {
+		country = unknownCountry
+	}
+	client := clientInfo(visit)
+
+	dims := [][2]string{
+		{DimTotal, ""},
+		{DimPage, visit.Page},
+		{DimCountry, country},
+		{DimBrowser, client.Browser},
+		{DimOS, client.OS},
+		{DimDevice, client.DeviceType},
+	}
+	if visit.City != "" {
+		// City names are not unique, so key them with the country
+		dims = append(dims, [2]string{DimCity, visit.City + keySeparator + country})
+	}
+
+	source := referrer.Source{Host: visit.ReferrerHost, Channel: visit.Channel}
+	if source.Channel == "" {
+		source = classifier.Classify(visit.Referrer, referrer.UTM{})
+	}
+	dims = append(dims, [2]string{DimChannel, source.Channel})
+	if source.Host != "" {
+		dims = append(dims, [2]string{DimReferrer, source.Host + keySeparator + source.Channel})
+	}
+
+	if visit.UTMSource != "" || visit.UTMMedium != "" || visit.UTMCampaign != "" {
+		value := strings.Join([]string{visit.UTMSource, visit.UTMMedium, visit.UTMCampaign}, keySeparator)
+		dims = append(dims, [2]string{DimCampaign, value})
+	}
+	if visit.ProjectID != "" {
+		dims = append(dims, [2]string{DimProject, visit.ProjectID})
+	}
+	return dims
+}
@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"portfolio-api/models"
+)
+
+// SessionTimeout ends a session after this much inactivity
+const SessionTimeout = 30 * time.Minute
+
+// Session is a run of visits by one visitor without a SessionTimeout gap
+type Session struct {
+	Visits []models.Visit
+}
+
+// Start is the time of the first visit
+func (s Session) Start() time.Time {
+	return s.Visits[0].CreatedAt
+}
+
+// End is the last activity: the latest visit or heartbeat
+func (s Session) End() time.Time {
+	end := s.Start()
+	for _, visit := range s.Visits {
+		if at := LastActivity(visit); at.After(end) {
+			end = at
+		}
+	}
+	return end
+}
+
+// Duration is the time between the first visit and the last activity
+func (s Session) Duration() time.Duration {
+	return s.End().Sub(s.Start())
+}
+
+// Bounced reports whether the session viewed a single page
+func (s Session) Bounced() bool {
+	return len(s.Visits) == 1
+}
+
+// EntryPage is the first page of the session
+func (s Session) EntryPage() string {
+	return s.Visits[0].Page
+}
+
+// ExitPage is the last page of the session
+func (s Session) ExitPage() string {
+	return s.Visits[len(s.Visits)-1].Page
+}
+
+// LastActivity returns when a visit was last known to be active
+func LastActivity(visit models.Visit) time.Time {
+	if visit.EndedAt != nil && visit.EndedAt.After(visit.CreatedAt) {
+		return *visit.EndedAt
+	}
+	return visit.CreatedAt
+}
+
+// GroupSessions splits visits into sessions per visitor, ordered by start.
+// Visitor IDs rotate daily, so sessions also end at midnight UTC.
+func GroupSessions(visits []models.Visit) []Session {
+	byVisitor := make(map[string][]models.Visit)
+	for _, visit := range visits {
+		key := visitorKey(visit)
+		byVisitor[key] = append(byVisitor[key], visit)
+	}
+
+	var sessions []Session
+	for _, visits := range byVisitor {
+		sort.Slice(visits, func(i, j int) bool { return visits[i].CreatedAt.Before(visits[j].CreatedAt) })
+
+		current := Session{Visits: visits[:1:1]}
+		for _, visit := range visits[1:] {
+			if visit.CreatedAt.Sub(current.End()) > SessionTimeout {
+				sessions = append(sessions, current)
+				current = Session{}
+			}
+			current.Visits = append(current.Visits, visit)
+		}
+		sessions = append(sessions, current)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Start().Before(sessions[j].Start()) })
+	return sessions
+}
@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+
+	"portfolio-api/models"
+)
+
+// keySeparator joins the parts of composite keys and rollup values
+const keySeparator = "\t"
+
+// sourceCount tallies the views and visitors of one channel, host or campaign
+type sourceCount struct {
+	key      string
+	views    int
+	visitors int
+}
+
+// sourceCounts sums the views and first-sighting visitors of one dimension
+// of the rollups within the query period
+func sourceCounts(rows []models.Rollup, q ViewQuery, dimension string) map[string]*sourceCount {
+	counts := make(map[string]*sourceCount)
+	for _, row := range rows {
+		if row.Dimension != dimension || row.PeriodStart.Before(q.From) || !row.PeriodStart.Before(q.To) {
+			continue
+		}
+		c := counts[row.Value]
+		if c == nil {
+			c = &sourceCount{key: row.Value}
+			counts[row.Value] = c
+		}
+		c.views += row.Views
+		c.visitors += row.NewVisitors
+	}
+	return counts
+}
+
+// topSources returns the n counts with the most visitors, then views; n <= 0 returns all
+func topSources(counts map[string]*sourceCount, n int) []*sourceCount {
+	sorted := make([]*sourceCount, 0, len(counts))
+	for _, c := range counts {
+		sorted = append(sorted, c)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.visitors != b.visitors {
+			return a.visitors > b.visitors
+		}
+		if a.views != b.views {
+			return a.views > b.views
+		}
+		return a.key < b.key
+	})
+
+	if n > 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// ComputeReferrerStats aggregates the rollups within the query period by
+// channel and referring host
+func ComputeReferrerStats(rows []models.Rollup, q ViewQuery) models.ReferrerStats {
+	stats := models.ReferrerStats{
+		From:      q.From.In(q.Location),
+		To:        q.To.In(q.Location),
+		Timezone:  q.Location.String(),
+		Channels:  []models.ChannelStat{},
+		Referrers: []models.ReferrerStat{},
+	}
+
+	for _, c := range topSources(sourceCounts(rows, q, DimChannel), 0) {
+		stats.Channels = append(stats.Channels, models.ChannelStat{
+			Channel:  c.key,
+			Views:    c.views,
+			Visitors: c.visitors,
+		})
+	}
+	for _, c := range topSources(sourceCounts(rows, q, DimReferrer), q.TopN) {
+		host, channel, _ := strings.Cut(c.key, keySeparator)
+		stats.Referrers = append(stats.Referrers, models.ReferrerStat{
+			Host:     host,
+			Channel:  channel,
+			Views:    c.views,
+			Visitors: c.visitors,
+		})
+	}
+	return stats
+}
+
+// ComputeCampaignStats aggregates the rollups within the query period by
+// UTM source, medium and campaign
+func ComputeCampaignStats(rows []models.Rollup, q ViewQuery) models.CampaignStats {
+	stats := models.CampaignStats{
+		From:      q.From.In(q.Location),
+		To:        q.To.In(q.Location),
+		Timezone:  q.Location.String(),
+		Campaigns: []models.CampaignStat{},
+	}
+
+	for _, c := range topSources(sourceCounts(rows, q, DimCampaign), q.TopN) {
+		parts := strings.SplitN(c.key, keySeparator, 3)
+		for len(parts) < 3 {
+			parts = append(parts, "")
+		}
+		stats.Campaigns = append(stats.Campaigns, models.CampaignStat{
+			Source:   parts[0],
+			Medium:   parts[1],
+			Campaign: parts[2],
+			Views:    c.views,
+			Visitors: c.visitors,
+		})
+	}
+	return stats
+}
@@ -0,0 +1,267 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"portfolio-api/models"
+	"portfolio-api/useragent"
+)
+
+// MaxBuckets bounds the length of a time series
+const MaxBuckets = 1000
+
+// unknownCountry groups visits without a country
+const unknownCountry = "Unknown"
+
+// ViewQuery describes the period and bucketing of a views report
+type ViewQuery struct {
+	// From and To bound the report, To exclusive
+	From time.Time
+	To   time.Time
+	// Now anchors the today/this week/this month counts
+	Now         time.Time
+	Location    *time.Location
+	Granularity Granularity
+	// TopN limits the top pages, locations, browsers and operating systems
+	TopN int
+}
+
+// BucketCount returns how many buckets the query's time series has
+func (q ViewQuery) BucketCount() int {
+	n := 0
+	for t := q.Granularity.Truncate(q.From.In(q.Location)); t.Before(q.To) && n <= MaxBuckets; t = q.Granularity.Next(t) {
+		n++
+	}
+	return n
+}
+
+// LoadFrom returns the earliest visit time ComputeViewStats needs: the start
+// of the report or of the current month, whichever comes first
+func (q ViewQuery) LoadFrom() time.Time {
+	monthStart := Month.Truncate(q.Now.In(q.Location))
+	weekStart := Week.Truncate(q.Now.In(q.Location))
+	from := q.From
+	for _, t := range []time.Time{monthStart, weekStart} {
+		if t.Before(from) {
+			from = t
+		}
+	}
+	return from
+}
+
+// LoadTo returns the latest visit time ComputeViewStats needs
+func (q ViewQuery) LoadTo() time.Time {
+	if q.Now.After(q.To) {
+		return q.Now
+	}
+	return q.To
+}
+
+// UseDailyRollups reports whether daily rollups can answer the query: its
+// buckets are days or longer, its days are UTC days and it starts at midnight
+func (q ViewQuery) UseDailyRollups() bool {
+	if q.Granularity == Hour {
+		return false
+	}
+	for _, t := range []time.Time{q.From, q.To, q.LoadFrom()} {
+		if _, offset := t.In(q.Location).Zone(); offset != 0 {
+			return false
+		}
+	}
+	return q.From.Equal(Day.Truncate(q.From.UTC())) &&
+		(q.To.Equal(Day.Truncate(q.To.UTC())) || !q.To.Before(q.Now))
+}
+
+// ComputeViewStats aggregates the rollups between LoadFrom and LoadTo: daily
+// rollups when UseDailyRollups allows, hourly ones otherwise. Rollups count
+// whole periods, so the report is precise to the hour or day.
+func ComputeViewStats(rows []models.Rollup, q ViewQuery) models.ViewStats {
+	now := q.Now.In(q.Location)
+	todayStart := Day.Truncate(now)
+	weekStart := Week.Truncate(now)
+	monthStart := Month.Truncate(now)
+
+	stats := models.ViewStats{
+		From:        q.From.In(q.Location),
+		To:          q.To.In(q.Location),
+		Granularity: string(q.Granularity),
+		Timezone:    q.Location.String(),
+	}
+
+	counts := map[string]map[string]int{}
+	for _, dim := range []string{DimPage, DimEntryPage, DimExitPage, DimCountry, DimCity, DimBrowser, DimOS, DimDevice} {
+		counts[dim] = make(map[string]int)
+	}
+	buckets := make(map[int64]int)
+	bucketVisitors := make(map[int64]int)
+	bounces, pages := 0, 0
+	seconds := 0.0
+
+	for _, row := range rows {
+		at := row.PeriodStart.In(q.Location)
+
+		if row.Dimension == DimTotal && !at.After(now) {
+			if !at.Before(todayStart) {
+				stats.ViewsToday += row.Views
+			}
+			if !at.Before(weekStart) {
+				stats.ViewsThisWeek += row.Views
+			}
+			if !at.Before(monthStart) {
+				stats.ViewsThisMonth += row.Views
+			}
+		}
+
+		if at.Before(q.From) || !at.Before(q.To) {
+			continue
+		}
+
+		switch row.Dimension {
+		case DimTotal:
+			stats.TotalViews += row.Views
+			stats.UniqueVisitors += row.NewVisitors
+			stats.Sessions += row.Sessions
+			bounces += row.Bounces
+			pages += row.SessionPages
+			seconds += row.SessionSeconds
+
+			bucket := q.Granularity.Truncate(at).Unix()
+			buckets[bucket] += row.Views
+			// Hourly rows know their own visitors; longer buckets add up
+			// each day's first sightings
+			if q.Granularity == Hour {
+				bucketVisitors[bucket] += row.Visitors
+			} else {
+				bucketVisitors[bucket] += row.NewVisitors
+			}
+		case DimEntryPage, DimExitPage:
+			counts[row.Dimension][row.Value] += row.Sessions
+		default:
+			if dim, ok := counts[row.Dimension]; ok {
+				dim[row.Value] += row.Views
+			}
+		}
+	}
+
+	if stats.Sessions > 0 {
+		sessions := float64(stats.Sessions)
+		stats.BounceRate = round1(float64(bounces) / sessions * 100)
+		stats.PagesPerSession = round1(float64(pages) / sessions)
+		stats.AvgSessionDuration = round1(seconds / sessions)
+	}
+
+	stats.TopPages = []models.PageStat{}
+	for _, entry := range topEntries(counts[DimPage], q.TopN) {
+		stats.TopPages = append(stats.TopPages, models.PageStat{Page: entry.key, Views: entry.count})
+	}
+
+	stats.TopEntryPages = []models.PageStat{}
+	for _, entry := range topEntries(counts[DimEntryPage], q.TopN) {
+		stats.TopEntryPages = append(stats.TopEntryPages, models.PageStat{Page: entry.key, Views: entry.count})
+	}
+
+	stats.TopExitPages = []models.PageStat{}
+	for _, entry := range topEntries(counts[DimExitPage], q.TopN) {
+		stats.TopExitPages = append(stats.TopExitPages, models.PageStat{Page: entry.key, Views: entry.count})
+	}
+
+	stats.ViewsByCountry = []models.CountryStat{}
+	for _, entry := range topEntries(counts[DimCountry], q.TopN) {
+		stats.ViewsByCountry = append(stats.ViewsByCountry, models.CountryStat{Country: entry.key, Views: entry.count})
+	}
+
+	stats.ViewsByCity = []models.CityStat{}
+	for _, entry := range topEntries(counts[DimCity], q.TopN) {
+		city, country, _ := strings.Cut(entry.key, keySeparator)
+		stats.ViewsByCity = append(stats.ViewsByCity, models.CityStat{City: city, Country: country, Views: entry.count})
+	}
+
+	stats.ViewsByBrowser = []models.BrowserStat{}
+	for _, entry := range topEntries(counts[DimBrowser], q.TopN) {
+		stats.ViewsByBrowser = append(stats.ViewsByBrowser, models.BrowserStat{Browser: entry.key, Views: entry.count})
+	}
+
+	stats.ViewsByOS = []models.OSStat{}
+	for _, entry := range topEntries(counts[DimOS], q.TopN) {
+		stats.ViewsByOS = append(stats.ViewsByOS, models.OSStat{OS: entry.key, Views: entry.count})
+	}
+
+	stats.ViewsByDevice = []models.DeviceStat{}
+	for _, entry := range topEntries(counts[DimDevice], 0) {
+		stats.ViewsByDevice = append(stats.ViewsByDevice, models.DeviceStat{DeviceType: entry.key, Views: entry.count})
+	}
+
+	stats.ViewsOverTime = []models.TimeStat{}
+	for t := q.Granularity.Truncate(q.From.In(q.Location)); t.Before(q.To); t = q.Granularity.Next(t) {
+		stats.ViewsOverTime = append(stats.ViewsOverTime, models.TimeStat{
+			Date:     q.Granularity.Label(t),
+			Views:    buckets[t.Unix()],
+			Visitors: bucketVisitors[t.Unix()],
+		})
+	}
+
+	return stats
+}
+
+// round1 rounds to one decimal place
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+// clientInfo returns the stored user-agent classification of a visit,
+// classifying visits recorded before it was stored on ingest
+func clientInfo(visit models.Visit) useragent.Info {
+	if visit.DeviceType == "" {
+		return useragent.Parse(visit.UserAgent)
+	}
+	info := useragent.Info{
+		Browser:        visit.Browser,
+		BrowserVersion: visit.BrowserVersion,
+		OS:             visit.OS,
+		DeviceType:     visit.DeviceType,
+	}
+	if info.Browser == "" {
+		info.Browser = useragent.Other
+	}
+	if info.OS == "" {
+		info.OS = useragent.Other
+	}
+	return info
+}
+
+// visitorKey identifies the visitor behind a visit. Visits recorded before
+// visitor IDs existed cannot be linked and count as one visitor each.
+func visitorKey(visit models.Visit) string {
+	if visit.VisitorID == "" {
+		return visit.ID
+	}
+	return visit.VisitorID
+}
+
+type entry struct {
+	key   string
+	count int
+}
+
+// topEntries returns the n largest counts, ties broken by key; n <= 0 returns all
+func topEntries(counts map[string]int, n int) []entry {
+	entries := make([]entry, 0, len(counts))
+	for key, count := range counts {
+		entries = append(entries, entry{key: key, count: count})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].key < entries[j].key
+	})
+
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
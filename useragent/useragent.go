@@ -0,0 +1,111 @@
+// Package useragent classifies User-Agent strings into browser, OS and device type
+package useragent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Device types
+const (
+	Desktop = "desktop"
+	Mobile  = "mobile"
+	Tablet  = "tablet"
+	Bot     = "bot"
+	Unknown = "unknown"
+)
+
+// Other is the browser or OS family of anything unrecognised
+const Other = "Other"
+
+// Info is the classification of a User-Agent string
+type Info struct {
+	Browser        string
+	BrowserVersion string
+	OS             string
+	DeviceType     string
+}
+
+type family struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// botPattern matches crawlers, monitors and HTTP libraries; the first group names the bot
+var botPattern = regexp.MustCompile(`(?i)([a-z0-9_.-]*(?:bot|crawler|spider|slurp|crawl)[a-z0-9_.-]*)|` +
+	`(facebookexternalhit|curl|wget|python-requests|python-urllib|go-http-client|okhttp|java/|libwww-perl|httpclient|axios|node-fetch|postmanruntime|lighthouse|pingdom|uptimerobot)`)
+
+// browsers are checked in order, since most user agents also claim to be
+// Mozilla, Safari and often Chrome; the first group captures the version
+var browsers = []family{
+	{"Edge", regexp.MustCompile(`(?:Edg|Edge|EdgA|EdgiOS)/(\d+)`)},
+	{"Opera", regexp.MustCompile(`(?:OPR|Opera)/(\d+)`)},
+	{"Samsung Internet", regexp.MustCompile(`SamsungBrowser/(\d+)`)},
+	{"Yandex", regexp.MustCompile(`YaBrowser/(\d+)`)},
+	{"Whale", regexp.MustCompile(`Whale/(\d+)`)},
+	{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/(\d+)`)},
+	{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS|HeadlessChrome)/(\d+)`)},
+	{"Safari", regexp.MustCompile(`Version/(\d+)[\d.]* (?:Mobile/\S+ )?Safari/`)},
+	{"Internet Explorer", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)(\d+)`)},
+}
+
+var operatingSystems = []family{
+	{"iOS", regexp.MustCompile(`iPhone|iPad|iPod`)},
+	{"Android", regexp.MustCompile(`Android`)},
+	{"Windows", regexp.MustCompile(`Windows`)},
+	{"ChromeOS", regexp.MustCompile(`CrOS`)},
+	{"macOS", regexp.MustCompile(`Macintosh|Mac OS X`)},
+	{"Linux", regexp.MustCompile(`Linux|X11`)},
+}
+
+var (
+	tabletPattern = regexp.MustCompile(`(?i)iPad|Tablet|Kindle|Silk/|PlayBook|Nexus (?:7|9|10)`)
+	mobilePattern = regexp.MustCompile(`(?i)Mobi|iPhone|iPod|Windows Phone|Opera Mini|BlackBerry`)
+)
+
+// Parse classifies a User-Agent header value
+func Parse(ua string) Info {
+	ua = strings.TrimSpace(ua)
+	if ua == "" {
+		return Info{Browser: Other, OS: Other, DeviceType: Unknown}
+	}
+
+	info := Info{Browser: Other, OS: Other}
+	for _, browser := range browsers {
+		if match := browser.pattern.FindStringSubmatch(ua); match != nil {
+			info.Browser = browser.name
+			info.BrowserVersion = match[1]
+			break
+		}
+	}
+	for _, os := range operatingSystems {
+		if os.pattern.MatchString(ua) {
+			info.OS = os.name
+			break
+		}
+	}
+
+	switch {
+	case botPattern.MatchString(ua):
+		info.DeviceType = Bot
+		info.Browser, info.BrowserVersion = botName(ua), ""
+	case tabletPattern.MatchString(ua), info.OS == "Android" && !strings.Contains(ua, "Mobile"):
+		info.DeviceType = Tablet
+	case mobilePattern.MatchString(ua):
+		info.DeviceType = Mobile
+	default:
+		info.DeviceType = Desktop
+	}
+
+	return info
+}
+
+// botName extracts a short bot name such as "Googlebot" or "curl"
+func botName(ua string) string {
+	match := botPattern.FindStringSubmatch(ua)
+	name := match[1]
+	if name == "" {
+		name = match[2]
+	}
+	return strings.TrimSuffix(name, "/")
+}
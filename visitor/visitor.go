@@ -0,0 +1,124 @@
+// Package visitor identifies visitors without storing who they are
+package visitor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"portfolio-api/repository"
+)
+
+// dayLayout names the UTC day a salt belongs to
+const dayLayout = "2006-01-02"
+
+// Hasher derives visitor IDs from a salted hash of IP address and user agent.
+// Each UTC day gets a fresh random salt, so an ID only identifies a visitor
+// for one day. The previous day's salt is kept so that visits started before
+// midnight can still be matched to their visitor; older salts are deleted and
+// their IDs cannot be traced back to an IP.
+type Hasher struct {
+	salts repository.VisitorSaltRepository
+
+	mu   sync.Mutex
+	day  string
+	salt []byte
+	// previous is the salt of the day before day
+	previous []byte
+}
+
+// NewHasher creates a Hasher that shares its salts through the repository
+func NewHasher(salts repository.VisitorSaltRepository) *Hasher {
+	return &Hasher{salts: salts}
+}
+
+// ID returns the visitor ID of a client seen at the given time. Pass a
+// visit's start to get the ID the visit was recorded with.
+func (h *Hasher) ID(ctx context.Context, at time.Time, ip, userAgent string) (string, error) {
+	salt, err := h.saltFor(ctx, at.UTC().Format(dayLayout))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.New()
+	sum.Write(salt)
+	sum.Write([]byte(ip))
+	sum.Write([]byte{0})
+	sum.Write([]byte(userAgent))
+	return hex.EncodeToString(sum.Sum(nil)[:16]), nil
+}
+
+// saltFor returns the salt of day, rotating the cached salts when the day
+// changes. A time from before the previous day is hashed with the current
+// salt: looking its own salt up would store it again after it was deleted.
+func (h *Hasher) saltFor(ctx context.Context, day string) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if day > h.day {
+		if err := h.rotate(ctx, day); err != nil {
+			return nil, err
+		}
+	}
+	if day == previousDay(h.day) {
+		return h.previous, nil
+	}
+	return h.salt, nil
+}
+
+// rotate makes day the current day; callers hold the lock
+func (h *Hasher) rotate(ctx context.Context, day string) error {
+	yesterday := previousDay(day)
+	if err := h.salts.DeleteBefore(ctx, yesterday); err != nil {
+		return err
+	}
+
+	previous := h.salt
+	if h.day != yesterday {
+		// Another instance may have hashed visits with yesterday's salt
+		salt, err := h.loadSalt(ctx, yesterday)
+		if err != nil {
+			return err
+		}
+		previous = salt
+	}
+	salt, err := h.loadSalt(ctx, day)
+	if err != nil {
+		return err
+	}
+
+	h.day, h.salt, h.previous = day, salt, previous
+	return nil
+}
+
+// loadSalt returns the stored salt of day, storing a random one if it has none
+func (h *Hasher) loadSalt(ctx context.Context, day string) ([]byte, error) {
+	candidate := make([]byte, 32)
+	if _, err := rand.Read(candidate); err != nil {
+		return nil, err
+	}
+	salt, err := h.salts.Salt(ctx, day, hex.EncodeToString(candidate))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(salt), nil
+}
+
+// previousDay names the day before day
+func previousDay(day string) string {
+	t, err := time.Parse(dayLayout, day)
+	if err != nil {
+		return ""
+	}
+	return t.AddDate(0, 0, -1).Format(dayLayout)
+}
+
+// OptedOut reports whether the request asks not to be tracked through the
+// Do Not Track or Global Privacy Control headers
+func OptedOut(r *http.Request) bool {
+	return r.Header.Get("DNT") == "1" || r.Header.Get("Sec-GPC") == "1"
+}
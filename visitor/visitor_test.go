@@ -0,0 +1,65 @@
+package visitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"portfolio-api/repository"
+)
+
+func TestHasherMatchesVisitsFromBeforeMidnight(t *testing.T) {
+	ctx := context.Background()
+	hasher := NewHasher(repository.NewMemory().Salts)
+
+	beforeMidnight := time.Date(2024, 1, 1, 23, 59, 59, 0, time.UTC)
+	afterMidnight := beforeMidnight.Add(2 * time.Second)
+
+	yesterday, err := hasher.ID(ctx, beforeMidnight, "203.0.113.7", "Mozilla/5.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	today, err := hasher.ID(ctx, afterMidnight, "203.0.113.7", "Mozilla/5.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if today == yesterday {
+		t.Fatal("visitor ID did not change with the day")
+	}
+
+	again, err := hasher.ID(ctx, beforeMidnight, "203.0.113.7", "Mozilla/5.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != yesterday {
+		t.Errorf("ID for a visit started before midnight = %s, want %s", again, yesterday)
+	}
+}
+
+func TestHasherLateVisitKeepsOldSaltDeleted(t *testing.T) {
+	ctx := context.Background()
+	salts := repository.NewMemory().Salts
+	hasher := NewHasher(salts)
+
+	old := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := old.AddDate(0, 0, 2)
+
+	if _, err := hasher.ID(ctx, old, "203.0.113.7", "Mozilla/5.0"); err != nil {
+		t.Fatal(err)
+	}
+	today, err := hasher.ID(ctx, now, "203.0.113.7", "Mozilla/5.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	late, err := hasher.ID(ctx, old, "203.0.113.7", "Mozilla/5.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if late != today {
+		t.Errorf("late visit ID = %s, want the current day's %s", late, today)
+	}
+	if salt, _ := salts.Salt(ctx, "2024-01-01", "probe"); salt != "probe" {
+		t.Error("the salt from two days before was not deleted, or was stored again")
+	}
+}